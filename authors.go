@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// postAuthor returns the author named in a post's front matter, looking at
+// author, then the first of authors, then params.author. Themes differ on
+// which they use.
+func postAuthor(fields map[string]interface{}) string {
+	if author := firstString(fields["author"]); author != "" {
+		return author
+	}
+	if author := firstString(fields["authors"]); author != "" {
+		return author
+	}
+	if params, ok := fields["params"].(map[string]interface{}); ok {
+		return firstString(params["author"])
+	}
+	return ""
+}
+
+// firstString returns value as a string, or its first item if it is a list.
+func firstString(value interface{}) string {
+	if items, ok := value.([]interface{}); ok {
+		if len(items) == 0 {
+			return ""
+		}
+		value = items[0]
+	}
+	if value == nil {
+		return ""
+	}
+	return frontMatterString(value)
+}
+
+// topAuthors returns up to limit authors with the most posts, busiest
+// first. Posts without an author count as "(unknown)".
+func topAuthors(posts []PostMeta, limit int) []string {
+	totals := make(map[string]int)
+	for _, post := range posts {
+		totals[authorName(post)]++
+	}
+
+	authors := make([]string, 0, len(totals))
+	for author := range totals {
+		authors = append(authors, author)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if totals[authors[i]] != totals[authors[j]] {
+			return totals[authors[i]] > totals[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+	if limit > 0 && len(authors) > limit {
+		authors = authors[:limit]
+	}
+	return authors
+}
+
+func authorName(post PostMeta) string {
+	if post.Author == "" {
+		return "(unknown)"
+	}
+	return post.Author
+}
+
+// printAuthorCounts writes a table of posts per month for the top authors,
+// with a row per month and a column per author.
+func printAuthorCounts(w io.Writer, stats Stats, limit int) {
+	authors := topAuthors(stats.Posts, limit)
+	counts := make(map[string]map[string]int) // month -> author -> posts
+	for _, post := range stats.Posts {
+		month := post.Date.Format("2006-01")
+		if counts[month] == nil {
+			counts[month] = make(map[string]int)
+		}
+		counts[month][authorName(post)]++
+	}
+
+	widths := make([]int, len(authors))
+	fmt.Fprintf(w, "\n%-8s", "Month")
+	for i, author := range authors {
+		widths[i] = max(len(author), 5)
+		fmt.Fprintf(w, "  %*s", widths[i], author)
+	}
+	fmt.Fprintln(w)
+
+	for _, month := range stats.Months {
+		fmt.Fprintf(w, "%-8s", month.Month)
+		for i, author := range authors {
+			fmt.Fprintf(w, "  %*d", widths[i], counts[month.Month][author])
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkPostCounts returns a post on every third day of 2024.
+func benchmarkPostCounts() map[string]int {
+	postCounts := make(map[string]int)
+	for day := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2024; day = day.AddDate(0, 0, 3) {
+		postCounts[day.Format("2006-01-02")] = 1 + day.Day()%3
+	}
+	return postCounts
+}
+
+func BenchmarkGenerateCalendarGrid(b *testing.B) {
+	postCounts := benchmarkPostCounts()
+	opts := CalendarOptions{ShowCounts: true, Colors: newColorScheme()}
+	month := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		generateCalendarGrid(month, postCounts, opts)
+	}
+}
+
+// BenchmarkCalendarRow compares the way generateCalendarGrid used to build
+// a row, joining a slice of cells, with the strings.Builder it uses now.
+func BenchmarkCalendarRow(b *testing.B) {
+	colors := newColorScheme()
+	cells := make([]string, 7)
+	for col := range cells {
+		cells[col] = colors.Post.Sprintf("%2d", col+1)
+	}
+
+	b.Run("join", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var rowParts []string
+			for _, cell := range cells {
+				rowParts = append(rowParts, cell)
+			}
+			_ = strings.Join(rowParts, " ")
+		}
+	})
+
+	b.Run("builder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var row strings.Builder
+			for col, cell := range cells {
+				if col > 0 {
+					row.WriteByte(' ')
+				}
+				row.WriteString(cell)
+			}
+			_ = row.String()
+		}
+	})
+}
+
+// writeSyntheticPosts creates count page bundles under dir/content/posts,
+// spread over the days of 2024, and returns the posts directory.
+func writeSyntheticPosts(tb testing.TB, dir string, count int) string {
+	tb.Helper()
+	postsPath := filepath.Join(dir, "content", "posts")
+	for i := 0; i < count; i++ {
+		bundle := filepath.Join(postsPath, fmt.Sprintf("post-%04d", i))
+		if err := os.MkdirAll(bundle, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i%366)
+		content := fmt.Sprintf("---\ntitle: Post %d\ndate: %s\ntags: [go, hugo]\n---\n\n%s",
+			i, date.Format("2006-01-02"), strings.Repeat("Some words about post number "+fmt.Sprint(i)+".\n", 20))
+		if i%10 == 0 {
+			content += "This one is sponsored.\n"
+		}
+		if err := os.WriteFile(filepath.Join(bundle, "index.md"), []byte(content), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return postsPath
+}
+
+// BenchmarkParsePostsFiltered reads 1,000 posts with --filter-regex
+// patterns, which are compiled once when arguments are parsed.
+func BenchmarkParsePostsFiltered(b *testing.B) {
+	dir := b.TempDir()
+	postsPath := writeSyntheticPosts(b, dir, 1000)
+	config := defaultConfig()
+	config.ProjectPath = dir
+	config.FilterRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)sponsored`),
+		regexp.MustCompile(`post number \d+7\.`),
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := parsePostsAndCount(io.Discard, filepath.Dir(postsPath), postsPath, config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParsePostFile parses a 5 KB post with typical front matter.
+func BenchmarkParsePostFile(b *testing.B) {
+	fixture := filepath.Join("testdata", "post.md")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := parsePostFile(fixture); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParsePostsAndCount walks and parses 500 posts.
+func BenchmarkParsePostsAndCount(b *testing.B) {
+	dir := b.TempDir()
+	postsPath := writeSyntheticPosts(b, dir, 500)
+	config := defaultConfig()
+	config.ProjectPath = dir
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, err := parsePostsAndCount(io.Discard, filepath.Dir(postsPath), postsPath, config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// BestTimeReport summarizes when posts have historically been published.
+type BestTimeReport struct {
+	Total         int     // Posts analyzed
+	Weekdays      [7]int  // Posts per weekday, indexed by time.Weekday
+	MonthDays     [32]int // Posts per day of the month, 1 through 31
+	BestWeekday   time.Weekday
+	BestMonthDay  int // Center of the busiest three-day window of the month
+	MonthDayPosts int // Posts in that window
+}
+
+// analyzeBestPublishTime counts posts by weekday and by day of the month
+// and picks the busiest of each. Days of the month are compared in
+// three-day windows, since a habit of posting mid-month rarely lands on
+// exactly the same date.
+func analyzeBestPublishTime(postMetas map[string][]PostMeta) BestTimeReport {
+	var report BestTimeReport
+	for _, metas := range postMetas {
+		for _, meta := range metas {
+			report.Total++
+			report.Weekdays[meta.Date.Weekday()]++
+			report.MonthDays[meta.Date.Day()]++
+		}
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if report.Weekdays[day] > report.Weekdays[report.BestWeekday] {
+			report.BestWeekday = day
+		}
+	}
+
+	for day := 1; day <= 31; day++ {
+		window := report.MonthDays[day]
+		if day > 1 {
+			window += report.MonthDays[day-1]
+		}
+		if day < 31 {
+			window += report.MonthDays[day+1]
+		}
+		if window > report.MonthDayPosts {
+			report.BestMonthDay = day
+			report.MonthDayPosts = window
+		}
+	}
+
+	return report
+}
+
+// printBestPublishTime writes the recommendation from report along with the
+// sample counts behind it.
+func printBestPublishTime(w io.Writer, report BestTimeReport) {
+	if report.Total == 0 {
+		fmt.Fprintln(w, "No posts to analyze.")
+		return
+	}
+
+	fmt.Fprintf(w, "You publish most on %ss and around the %s of each month.\n",
+		report.BestWeekday, ordinal(report.BestMonthDay))
+	fmt.Fprintf(w, "  %s: %d of %d posts (%.0f%%)\n", report.BestWeekday,
+		report.Weekdays[report.BestWeekday], report.Total,
+		percent(report.Weekdays[report.BestWeekday], report.Total))
+	fmt.Fprintf(w, "  %s ±1 day: %d of %d posts (%.0f%%)\n", ordinal(report.BestMonthDay),
+		report.MonthDayPosts, report.Total, percent(report.MonthDayPosts, report.Total))
+	if report.Total < 20 {
+		fmt.Fprintln(w, "  Based on fewer than 20 posts, so treat this as a rough guess.")
+	}
+
+	fmt.Fprintln(w)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		fmt.Fprintf(w, "%-10s %4d\n", day, report.Weekdays[day])
+	}
+}
+
+// ordinal formats n as 1st, 2nd, 3rd, 4th, and so on.
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func percent(part, total int) float64 {
+	return 100 * float64(part) / float64(total)
+}
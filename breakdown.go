@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"sort"
+)
+
+// breakdownGroup is one calendar in a breakdown view, such as the posts of
+// a single author.
+type breakdownGroup struct {
+	Label  string
+	Counts map[string]int
+}
+
+// authorGroups splits posts by author into calendar groups, keeping the
+// limit authors with the most posts.
+func authorGroups(posts []PostMeta, limit int) []breakdownGroup {
+	var groups []breakdownGroup
+	for _, author := range topAuthors(posts, limit) {
+		counts := make(map[string]int)
+		for _, post := range posts {
+			if authorName(post) == author {
+				counts[post.Date.Format("2006-01-02")]++
+			}
+		}
+		groups = append(groups, breakdownGroup{Label: author, Counts: counts})
+	}
+	return groups
+}
+
+// tagGroups splits posts by tag into calendar groups, keeping the limit
+// most used tags, most used first. A post with several tags is counted in
+// each of them.
+func tagGroups(posts []PostMeta, limit int) []breakdownGroup {
+	byTag := make(map[string]map[string]int)
+	totals := make(map[string]int)
+	for _, post := range posts {
+		dateKey := post.Date.Format("2006-01-02")
+		for _, tag := range post.Tags {
+			if byTag[tag] == nil {
+				byTag[tag] = make(map[string]int)
+			}
+			byTag[tag][dateKey]++
+			totals[tag]++
+		}
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if totals[tags[i]] != totals[tags[j]] {
+			return totals[tags[i]] > totals[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+
+	groups := make([]breakdownGroup, len(tags))
+	for i, tag := range tags {
+		groups[i] = breakdownGroup{Label: tag, Counts: byTag[tag]}
+	}
+	return groups
+}
+
+// renderBreakdown draws a labeled calendar for each group in turn.
+func renderBreakdown(w io.Writer, groups []breakdownGroup, monthFilter *string, opts CalendarOptions) {
+	for _, group := range groups {
+		total := 0
+		for _, count := range group.Counts {
+			total += count
+		}
+		noun := "posts"
+		if total == 1 {
+			noun = "post"
+		}
+		opts.Colors.Day.Fprintf(w, "%s (%d %s)\n\n", group.Label, total, noun)
+		renderCalendars(w, group.Counts, monthFilter, opts)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Cadence is a posting target such as 3 posts per week.
+type Cadence struct {
+	Count  int
+	Period string // "day", "week", or "month"
+}
+
+// parseCadence parses a target written as POSTS/PERIOD, like "3/week".
+func parseCadence(s string) (Cadence, error) {
+	countStr, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Cadence{}, fmt.Errorf("invalid cadence '%s', expected POSTS/PERIOD", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 {
+		return Cadence{}, fmt.Errorf("invalid cadence post count '%s'", countStr)
+	}
+	period = strings.ToLower(period)
+	if period != "day" && period != "week" && period != "month" {
+		return Cadence{}, fmt.Errorf("invalid cadence period '%s', expected day, week, or month", period)
+	}
+	return Cadence{Count: count, Period: period}, nil
+}
+
+// String returns the cadence in the form it was given on the command line.
+func (c Cadence) String() string {
+	return fmt.Sprintf("%d/%s", c.Count, c.Period)
+}
+
+// monthTarget returns the number of posts the cadence asks for in the month
+// containing month, rounded to the nearest post. Weekly targets are spread
+// over the days of the month, so a 31-day month expects a little more
+// than four weeks' worth.
+func (c Cadence) monthTarget(month time.Time) int {
+	days := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	switch c.Period {
+	case "day":
+		return c.Count * days
+	case "week":
+		return int(math.Round(float64(c.Count) * float64(days) / 7))
+	}
+	return c.Count
+}
+
+// weekTarget returns the number of posts the cadence asks for in a week,
+// rounded to the nearest post. Monthly targets are spread over the 52
+// weeks of the year.
+func (c Cadence) weekTarget() int {
+	switch c.Period {
+	case "day":
+		return c.Count * 7
+	case "month":
+		return int(math.Round(float64(c.Count) * 12 / 52))
+	}
+	return c.Count
+}
+
+// cadenceColor returns red for a count below target, green for on target,
+// and blue for above target.
+func cadenceColor(posts, target int) *color.Color {
+	switch {
+	case posts < target:
+		return color.New(color.FgRed)
+	case posts > target:
+		return color.New(color.FgBlue)
+	}
+	return color.New(color.FgGreen)
+}
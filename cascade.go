@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readCascade returns the cascade front matter of dir/_index.md, or nil if
+// the section has no _index.md or it sets no cascade. Only the map form of
+// cascade is supported, not the list of targeted blocks. Parse errors are
+// written to w as warnings.
+func readCascade(w io.Writer, dir string, config *Config) map[string]interface{} {
+	indexPath := filepath.Join(dir, "_index.md")
+	if _, err := os.Stat(indexPath); err != nil {
+		return nil
+	}
+
+	frontMatter, _, err := parsePostFile(indexPath)
+	if err != nil {
+		fmt.Fprintf(w, "Warning: Could not parse section file %s: %v\n", config.displayPath(indexPath), err)
+		return nil
+	}
+	cascade, _ := frontMatter.Fields["cascade"].(map[string]interface{})
+	return cascade
+}
+
+// mergeCascade returns the values of parent overridden by those of child,
+// as a section's cascade overrides the one it inherits.
+func mergeCascade(parent, child map[string]interface{}) map[string]interface{} {
+	if len(child) == 0 {
+		return parent
+	}
+	merged := make(map[string]interface{}, len(parent)+len(child))
+	for key, value := range parent {
+		merged[key] = value
+	}
+	for key, value := range child {
+		merged[key] = value
+	}
+	return merged
+}
+
+// applyCascade returns frontMatter with any fields it doesn't set itself
+// filled in from cascade.
+func applyCascade(frontMatter *PostFrontMatter, cascade map[string]interface{}) (*PostFrontMatter, error) {
+	if len(cascade) == 0 {
+		return frontMatter, nil
+	}
+
+	fields := make(map[string]interface{}, len(frontMatter.Fields)+len(cascade))
+	for key, value := range cascade {
+		fields[key] = value
+	}
+	for key, value := range frontMatter.Fields {
+		fields[key] = value
+	}
+
+	// Round trip through YAML so the typed fields are decoded the same way
+	// as they are from a post file
+	data, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var merged PostFrontMatter
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	merged.Fields = fields
+	return &merged, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// remoteSchemes are the URL prefixes of project paths that are cloned
+// rather than read from disk.
+var remoteSchemes = []string{"http://", "https://", "git+https://", "git+ssh://"}
+
+// isRemoteProject reports whether path is a repository URL to clone.
+func isRemoteProject(path string) bool {
+	for _, scheme := range remoteSchemes {
+		if strings.HasPrefix(path, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoShorthand matches OWNER/REPO project paths.
+var repoShorthand = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// expandRepoShorthand turns an OWNER/REPO project path into the URL of the
+// repository on GitHub, or GitLab if gitlab is set. Paths that exist on
+// disk are left alone, since they're more likely a local directory.
+func expandRepoShorthand(path string, gitlab bool) string {
+	if !repoShorthand.MatchString(path) || strings.HasPrefix(path, ".") {
+		return path
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	host := "github.com"
+	if gitlab {
+		host = "gitlab.com"
+	}
+	return "https://" + host + "/" + path + ".git"
+}
+
+// cloneProject clones the repository at url in a new temporary directory and
+// returns the directory, which the caller removes. The clone is shallow
+// unless fullHistory is set. Git's progress is written to progress.
+func cloneProject(url string, fullHistory bool, progress io.Writer) (string, error) {
+	// git understands the plain schemes, not the git+ forms
+	url = strings.TrimPrefix(url, "git+")
+
+	dir, err := os.MkdirTemp("", "hugo-calendar-")
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(progress, "Cloning %s...\n", url)
+	args := []string{"clone", "--progress"}
+	if !fullHistory {
+		args = append(args, "--depth=1")
+	}
+	cmd := exec.Command("git", append(args, url, dir)...)
+	cmd.Stdout = progress
+	cmd.Stderr = progress
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("git clone %s: %v", url, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import "github.com/fatih/color"
+
+// ColorScheme holds the colors used to draw calendar cells. It is created
+// once, after --no-color has been applied, and shared by every grid.
+type ColorScheme struct {
+	Day             *color.Color // Day without posts
+	EmptyMonthDay   *color.Color // Day in a month without any posts
+	RestDay         *color.Color // Day that doesn't count against streaks
+	Holiday         *color.Color // Holiday without posts
+	Post            *color.Color // Day with posts
+	IncompletePost  *color.Color // Day whose posts are all incomplete
+	BusyPost        *color.Color // Day above the --percentile-days threshold
+	HolidayPost     *color.Color
+	BusyHolidayPost *color.Color
+	TargetDay       *color.Color // Day picked by --day-of-month without posts
+	Highlight       *color.Color // Day from --highlight-dates without posts
+	HighlightPost   *color.Color
+	Anniversary     *color.Color // The first post's day with --counts, where there's no room for a cake
+	Today           *color.Color
+}
+
+func newColorScheme() *ColorScheme {
+	return &ColorScheme{
+		Day:             color.New(color.FgWhite),
+		EmptyMonthDay:   color.New(color.FgWhite, color.Faint),
+		RestDay:         color.New(color.FgWhite, color.Faint, color.Italic),
+		Holiday:         color.New(color.FgRed, color.Underline),
+		Post:            color.New(color.FgHiGreen, color.Bold),
+		IncompletePost:  color.New(color.FgGreen, color.Faint),
+		BusyPost:        color.New(color.FgHiRed, color.Bold),
+		HolidayPost:     color.New(color.FgHiGreen, color.Bold, color.Underline),
+		BusyHolidayPost: color.New(color.FgHiRed, color.Bold, color.Underline),
+		TargetDay:       color.New(color.FgCyan, color.Underline),
+		Highlight:       color.New(color.FgWhite, color.BgBlue),
+		HighlightPost:   color.New(color.FgHiGreen, color.Bold, color.BgBlue),
+		Anniversary:     color.New(color.FgHiMagenta, color.Bold),
+		Today:           color.New(color.FgBlack, color.BgWhite),
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dumpConfig writes config as YAML with secrets redacted.
+func dumpConfig(w io.Writer, config *Config) error {
+	dump := *config
+	if dump.WebhookSecret != "" {
+		dump.WebhookSecret = "[REDACTED]"
+	}
+	// Slack webhook URLs carry their own credentials
+	if dump.SlackWebhook != "" {
+		dump.SlackWebhook = "[REDACTED]"
+	}
+	if dump.WebhookURL != "" {
+		dump.WebhookURL = redactURL(dump.WebhookURL)
+	}
+
+	data, err := marshalConfig(&dump)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// redactURL hides the user info and query of rawURL, where webhook URLs
+// usually carry their tokens, and keeps the rest for reference.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "[REDACTED]"
+	}
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = "REDACTED"
+	}
+	return u.String()
+}
+
+func marshalConfig(config interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unsavedKeys are the config keys --migrate-config leaves out: the project,
+// which is always chosen on the command line, and the months to show,
+// which are picked per run and often resolved from relative values such as
+// --month last or --year.
+var unsavedKeys = map[string]bool{"project-path": true, "month": true, "from": true, "to": true, "week": true}
+
+// savedConfig returns config as YAML with only the options that were set,
+// either on the command line or in a config file, rather than everything
+// resolved from them. Options left at their defaults aren't written.
+func savedConfig(config *Config) ([]byte, error) {
+	var resolved, defaults yaml.Node
+	if err := resolved.Encode(config); err != nil {
+		return nil, err
+	}
+	if err := defaults.Encode(defaultConfig()); err != nil {
+		return nil, err
+	}
+	defaultValues := make(map[string]string)
+	for i := 0; i+1 < len(defaults.Content); i += 2 {
+		defaultValues[defaults.Content[i].Value] = yamlString(defaults.Content[i+1])
+	}
+
+	// --year and --quarter imply these unless they're given too
+	implied := map[string]bool{
+		"only-with-posts":   config.Given["--year"] && !config.Given["--only-with-posts"],
+		"show-empty-months": config.Given["--quarter"] && !config.Given["--show-empty-months"],
+	}
+
+	saved := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(resolved.Content); i += 2 {
+		key, value := resolved.Content[i], resolved.Content[i+1]
+		if unsavedKeys[key.Value] || implied[key.Value] || yamlString(value) == defaultValues[key.Value] {
+			continue
+		}
+		saved.Content = append(saved.Content, key, value)
+	}
+	return marshalConfig(saved)
+}
+
+// yamlString returns node as YAML text, for comparing values.
+func yamlString(node *yaml.Node) string {
+	data, _ := yaml.Marshal(node)
+	return string(data)
+}
+
+// migrateConfig saves the options in config as a YAML config file at path.
+// An existing file is only replaced after showing what would change on w
+// and getting confirmation on stdin.
+func migrateConfig(w io.Writer, path string, config *Config) error {
+	data, err := savedConfig(config)
+	if err != nil {
+		return err
+	}
+
+	existing, err := os.ReadFile(path)
+	if err == nil {
+		if bytes.Equal(existing, data) {
+			fmt.Fprintf(w, "%s is already up to date\n", path)
+			return nil
+		}
+
+		fmt.Fprintf(w, "%s already exists. Changes:\n", path)
+		for _, line := range diffLines(strings.Split(string(existing), "\n"), strings.Split(string(data), "\n")) {
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintf(w, "Overwrite %s? [y/N] ", path)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(w, "Not overwritten.")
+			return nil
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Wrote configuration to %s\n", path)
+	return nil
+}
+
+// diffLines returns the lines removed from old ("- ") and added in new
+// ("+ "), in order, based on their longest common subsequence. Unchanged
+// lines are left out.
+func diffLines(old, new []string) []string {
+	// lcs[i][j] is the length of the LCS of old[i:] and new[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case i < len(old) && j < len(new) && old[i] == new[j]:
+			i++
+			j++
+		case i < len(old) && (j == len(new) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+old[i])
+			i++
+		default:
+			diff = append(diff, "+ "+new[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// globalConfigPath returns the location of the user's config file, which
+// follows $XDG_CONFIG_HOME when it is set.
+func globalConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "hugo-calendar", "config.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "hugo-calendar", "config.yaml")
+}
+
+// projectConfigPath returns the location of a project's own config file.
+func projectConfigPath(projectPath string) string {
+	return filepath.Join(projectPath, ".hugo-calendar.yaml")
+}
+
+// loadConfigFiles applies the global config file and then the project's
+// config file to config, returning the paths of the files it read. Missing
+// files are skipped.
+func loadConfigFiles(config *Config, projectPath string) ([]string, error) {
+	paths := []string{globalConfigPath()}
+	if projectPath != "" {
+		paths = append(paths, projectConfigPath(projectPath))
+	}
+
+	var loaded []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("config file %s: %v", path, err)
+		}
+		loaded = append(loaded, path)
+	}
+
+	// The project is always chosen on the command line, even if a saved
+	// config names one
+	config.ProjectPath = ""
+	return loaded, nil
+}
+
+// MarshalYAML writes a holiday calendar as its country code, or null for a
+// calendar of only custom holidays, which come from custom-holidays.
+func (h *HolidayCalendar) MarshalYAML() (interface{}, error) {
+	if h.country == "" {
+		return nil, nil
+	}
+	return h.country, nil
+}
+
+// UnmarshalYAML reads a holiday calendar from its country code.
+func (h *HolidayCalendar) UnmarshalYAML(node *yaml.Node) error {
+	var country string
+	if err := node.Decode(&country); err != nil {
+		return err
+	}
+	// Older saved configs wrote custom-only calendars as an empty country
+	if country == "" {
+		*h = HolidayCalendar{years: make(map[int]map[string]string)}
+		return nil
+	}
+	calendar := newHolidayCalendar(country)
+	if calendar == nil {
+		return fmt.Errorf("unsupported holiday country '%s', expected one of %s",
+			country, strings.Join(supportedCountries(), ", "))
+	}
+	*h = *calendar
+	return nil
+}
+
+// MarshalYAML writes rest days with weekday names rather than numbers.
+func (r RestDays) MarshalYAML() (interface{}, error) {
+	weekdays := []string{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if r.Weekdays[day] {
+			weekdays = append(weekdays, day.String())
+		}
+	}
+
+	return struct {
+		Holidays *HolidayCalendar `yaml:"holidays"`
+		Weekdays []string         `yaml:"weekdays"`
+	}{r.Holidays, weekdays}, nil
+}
+
+// UnmarshalYAML reads rest days written by MarshalYAML.
+func (r *RestDays) UnmarshalYAML(node *yaml.Node) error {
+	var value struct {
+		Holidays *HolidayCalendar `yaml:"holidays"`
+		Weekdays []string         `yaml:"weekdays"`
+	}
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+
+	r.Holidays = value.Holidays
+	r.Weekdays = nil
+	if len(value.Weekdays) > 0 {
+		weekdays, err := parseRestWeekdays(strings.Join(value.Weekdays, ","))
+		if err != nil {
+			return err
+		}
+		r.Weekdays = weekdays
+	}
+	return nil
+}
+
+// MarshalYAML writes a cadence in its POSTS/PERIOD form.
+func (c Cadence) MarshalYAML() (interface{}, error) {
+	return c.String(), nil
+}
+
+// UnmarshalYAML reads a cadence in its POSTS/PERIOD form.
+func (c *Cadence) UnmarshalYAML(node *yaml.Node) error {
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	cadence, err := parseCadence(value)
+	if err != nil {
+		return err
+	}
+	*c = cadence
+	return nil
+}
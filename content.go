@@ -0,0 +1,160 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// imageShortcodePattern matches Hugo shortcodes that embed images, in both
+// the {{< >}} and {{% %}} forms.
+var imageShortcodePattern = regexp.MustCompile(`\{\{[<%]\s*(figure|img|image|gallery)\b`)
+
+// externalLinkPattern matches absolute http(s) URLs, whether they appear
+// inside a Markdown link, an HTML attribute, or bare in the text.
+var externalLinkPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>()\[\]"']+`)
+
+// countImages counts Markdown images, HTML <img> tags, and image shortcodes
+// in a post body.
+func countImages(postBody string) int {
+	count := strings.Count(postBody, "![")
+	count += strings.Count(strings.ToLower(postBody), "<img")
+	count += len(imageShortcodePattern.FindAllStringIndex(postBody, -1))
+	return count
+}
+
+// countLinks counts external links in a post body.
+func countLinks(postBody string) int {
+	return len(externalLinkPattern.FindAllStringIndex(postBody, -1))
+}
+
+// warnLinkHeavyDays reports days with more than 20 external links across
+// all of their posts. It only prints anything with --verbose.
+func warnLinkHeavyDays(postCounts map[string]int, postMetas map[string][]PostMeta) {
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		links := 0
+		for _, meta := range postMetas[dateKey] {
+			links += meta.LinkCount
+		}
+		if links > 20 {
+			logVerbose("Warning: %s has %d external links", dateKey, links)
+		}
+	}
+}
+
+// countCodeBlocks counts fenced code blocks in a post body, returning the
+// total and how many of those name a language (```go rather than ```).
+func countCodeBlocks(postBody string) (total, tagged int) {
+	inBlock := false
+	for _, line := range strings.Split(postBody, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if inBlock {
+			inBlock = false
+			continue
+		}
+		inBlock = true
+		total++
+		if strings.TrimSpace(strings.TrimLeft(trimmed, "`")) != "" {
+			tagged++
+		}
+	}
+	return total, tagged
+}
+
+// countHeadings counts ATX-style Markdown headings by level, where index 0
+// holds H1 and index 5 holds H6. Lines inside fenced code blocks are
+// skipped since a shell comment is not a heading.
+func countHeadings(postBody string) [6]int {
+	var counts [6]int
+	inBlock := false
+	for _, line := range strings.Split(postBody, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inBlock = !inBlock
+			continue
+		}
+		if inBlock || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		if level > 6 {
+			continue
+		}
+		// A heading needs a space after the hashes, otherwise it's a tag
+		// like #golang
+		if rest := trimmed[level:]; rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		counts[level-1]++
+	}
+	return counts
+}
+
+// readabilityScore approximates the Flesch reading ease of a post body.
+// Higher is easier; most prose lands between 30 and 80. Code blocks are
+// ignored and syllables are estimated by counting vowel groups, which is
+// rough but good enough to compare posts with each other.
+func readabilityScore(postBody string) float64 {
+	var prose []string
+	inBlock := false
+	for _, line := range strings.Split(postBody, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inBlock = !inBlock
+			continue
+		}
+		if !inBlock {
+			prose = append(prose, line)
+		}
+	}
+	text := strings.Join(prose, "\n")
+
+	words := 0
+	syllables := 0
+	for _, field := range strings.Fields(text) {
+		word := strings.ToLower(strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		}))
+		if word == "" {
+			continue
+		}
+		words++
+		syllables += countSyllables(word)
+	}
+	if words == 0 {
+		return 0
+	}
+
+	sentences := len(sentenceEndPattern.FindAllStringIndex(text, -1))
+	if sentences == 0 {
+		sentences = 1
+	}
+
+	return 206.835 - 1.015*float64(words)/float64(sentences) - 84.6*float64(syllables)/float64(words)
+}
+
+// sentenceEndPattern matches a run of sentence-ending punctuation.
+var sentenceEndPattern = regexp.MustCompile(`[.!?]+(\s|$)`)
+
+// countSyllables estimates syllables in a lowercase word as the number of
+// vowel groups, not counting a silent trailing e.
+func countSyllables(word string) int {
+	count := 0
+	previousVowel := false
+	for _, r := range word {
+		isVowel := strings.ContainsRune("aeiouy", r)
+		if isVowel && !previousVowel {
+			count++
+		}
+		previousVowel = isVowel
+	}
+	if strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") && count > 1 {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
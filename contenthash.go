@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// contentHash returns the SHA-256 of a post body as hex.
+func contentHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashCachePath returns where the content hashes of a project are kept
+// between runs, under $XDG_CACHE_HOME or ~/.cache.
+func hashCachePath(projectPath string) string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "hugo-calendar", "hashes-"+projectHash(projectPath)+".json")
+}
+
+// reportContentChanges compares the body hashes of posts with those saved
+// by the last run, writes a line for each post whose body changed, and
+// saves the new hashes. Paths are stored relative to contentRoot so the
+// cache survives the project moving.
+func reportContentChanges(w io.Writer, cachePath, contentRoot string, posts []PostMeta) error {
+	previous := make(map[string]string)
+	data, err := os.ReadFile(cachePath)
+	if err == nil {
+		if err := json.Unmarshal(data, &previous); err != nil {
+			return fmt.Errorf("%s: %v", cachePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	current := make(map[string]string, len(posts))
+	changed := 0
+	for _, post := range posts {
+		relPath, err := filepath.Rel(contentRoot, post.Path)
+		if err != nil {
+			relPath = post.Path
+		}
+		current[relPath] = post.ContentHash
+		if hash, ok := previous[relPath]; ok && hash != post.ContentHash {
+			fmt.Fprintf(w, "CHANGED  %s  %s\n", post.Date.Format("2006-01-02"), post.Title)
+			changed++
+		}
+	}
+
+	if len(previous) == 0 {
+		fmt.Fprintf(w, "Recorded content hashes for %d posts; run again to see changes.\n", len(current))
+	} else if changed == 0 {
+		fmt.Fprintln(w, "No posts changed since the last run.")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+	data, err = json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cachePath, data, 0644)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// TagCorrelation compares the busyness of days with and without a tag.
+type TagCorrelation struct {
+	Tag        string
+	WithTag    float64 // Average posts on active days with a post using the tag
+	WithoutTag float64 // Average posts on the other active days
+	Difference float64
+}
+
+// correlateTags works out, for each tag, the average number of posts on
+// the active days that include it and on those that don't, ranked by the
+// difference, largest first.
+func correlateTags(postCounts map[string]int, postMetas map[string][]PostMeta) []TagCorrelation {
+	daysByTag := make(map[string]map[string]bool)
+	totalPosts, totalDays := 0, 0
+	for dateKey, metas := range postMetas {
+		if postCounts[dateKey] == 0 {
+			continue
+		}
+		totalPosts += postCounts[dateKey]
+		totalDays++
+		for _, meta := range metas {
+			for _, tag := range meta.Tags {
+				if daysByTag[tag] == nil {
+					daysByTag[tag] = make(map[string]bool)
+				}
+				daysByTag[tag][dateKey] = true
+			}
+		}
+	}
+
+	var correlations []TagCorrelation
+	for tag, days := range daysByTag {
+		posts := 0
+		for dateKey := range days {
+			posts += postCounts[dateKey]
+		}
+		correlation := TagCorrelation{Tag: tag, WithTag: float64(posts) / float64(len(days))}
+		if otherDays := totalDays - len(days); otherDays > 0 {
+			correlation.WithoutTag = float64(totalPosts-posts) / float64(otherDays)
+		}
+		correlation.Difference = correlation.WithTag - correlation.WithoutTag
+		correlations = append(correlations, correlation)
+	}
+
+	sort.Slice(correlations, func(i, j int) bool {
+		if correlations[i].Difference != correlations[j].Difference {
+			return correlations[i].Difference > correlations[j].Difference
+		}
+		return correlations[i].Tag < correlations[j].Tag
+	})
+	return correlations
+}
+
+// printTagCorrelations writes correlations as a Markdown table.
+func printTagCorrelations(w io.Writer, correlations []TagCorrelation) {
+	if len(correlations) == 0 {
+		fmt.Fprintln(w, "No tagged posts found.")
+		return
+	}
+
+	width := len("Tag")
+	for _, correlation := range correlations {
+		width = max(width, len(correlation.Tag))
+	}
+
+	fmt.Fprintf(w, "| %-*s | Avg posts w/ tag | Avg posts w/o tag |     Δ |\n", width, "Tag")
+	fmt.Fprintf(w, "|-%s-|-----------------:|------------------:|------:|\n", strings.Repeat("-", width))
+	for _, correlation := range correlations {
+		fmt.Fprintf(w, "| %-*s | %16.2f | %17.2f | %+5.2f |\n",
+			width, correlation.Tag, correlation.WithTag, correlation.WithoutTag, correlation.Difference)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// readDateList reads a file of YYYY-MM-DD dates, one per line, into a set
+// keyed by date. Blank lines and lines starting with # are skipped.
+func readDateList(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dates := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid date '%s', expected YYYY-MM-DD", path, lineNumber, line)
+		}
+		dates[date.Format("2006-01-02")] = true
+	}
+	return dates, scanner.Err()
+}
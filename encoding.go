@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// outputTransformer returns the transformer that converts UTF-8 output to
+// the named encoding, or nil when no conversion is needed. Characters the
+// target can't represent become '?'.
+func outputTransformer(name string) (transform.Transformer, error) {
+	if isUTF8(name) {
+		return nil, nil
+	}
+	switch strings.ToUpper(name) {
+	case "ASCII", "US-ASCII":
+		return replaceAbove(0x7f), nil
+	case "LATIN-1", "LATIN1", "ISO-8859-1":
+		return transform.Chain(replaceAbove(0xff), charmap.ISO8859_1.NewEncoder()), nil
+	default:
+		return nil, fmt.Errorf("unsupported output encoding '%s', expected UTF-8, ASCII, or LATIN-1", name)
+	}
+}
+
+// isUTF8 reports whether the named output encoding is UTF-8, the default.
+func isUTF8(name string) bool {
+	switch strings.ToUpper(name) {
+	case "", "UTF-8", "UTF8":
+		return true
+	}
+	return false
+}
+
+// replaceAbove replaces every rune greater than max with '?'.
+func replaceAbove(max rune) transform.Transformer {
+	return runes.Map(func(r rune) rune {
+		if r > max {
+			return '?'
+		}
+		return r
+	})
+}
+
+// setOutputEncoding wraps stdout so everything written to it is converted
+// through t. It returns the writer to use in place of stdout and a function
+// that must be called before exiting to flush any remaining output.
+func setOutputEncoding(stdout io.Writer, t transform.Transformer) (io.Writer, func()) {
+	encoded := transform.NewWriter(stdout, t)
+	return encoded, func() { encoded.Close() }
+}
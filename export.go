@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// projectHash returns a short, stable identifier for a project path so that
+// exported metrics can be told apart without revealing the directory name.
+func projectHash(projectPath string) string {
+	if absPath, err := filepath.Abs(projectPath); err == nil {
+		projectPath = absPath
+	}
+	return shortHash(projectPath, 12)
+}
+
+// shortHash returns the first length hex digits of the SHA-256 of s.
+func shortHash(s string, length int) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:length]
+}
+
+// sortedDateKeys returns the keys of postCounts in ascending date order.
+func sortedDateKeys(postCounts map[string]int) []string {
+	keys := make([]string, 0, len(postCounts))
+	for dateKey := range postCounts {
+		keys = append(keys, dateKey)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exportInflux writes one InfluxDB line protocol point per active day.
+func exportInflux(filePath, projectPath string, postCounts map[string]int) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	site := projectHash(projectPath)
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		count := postCounts[dateKey]
+		if count == 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil {
+			continue
+		}
+		_, err = fmt.Fprintf(file, "hugo_posts,site=%s count=%di,date=\"%s\" %d\n",
+			site, count, dateKey, date.UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Close()
+}
+
+// exportMetrics writes posting statistics in the OpenMetrics text format
+// to filePath, or to stderr when filePath is empty.
+func exportMetrics(stderr io.Writer, filePath string, stats Stats, walkStats WalkStats) error {
+	if filePath == "" {
+		return writeMetrics(stderr, stats, walkStats)
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := writeMetrics(file, stats, walkStats); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func writeMetrics(w io.Writer, stats Stats, walkStats WalkStats) error {
+	metrics := []struct {
+		name, kind, help string
+		value            int
+	}{
+		{"hugo_calendar_posts", "counter", "Published posts counted.", stats.TotalPosts},
+		{"hugo_calendar_draft_posts", "counter", "Draft posts skipped.", walkStats.Drafts},
+		{"hugo_calendar_incomplete_posts", "counter", "Posts missing required front matter.", stats.Incomplete},
+		{"hugo_calendar_active_days", "gauge", "Days with at least one post.", stats.ActiveDays},
+		{"hugo_calendar_current_streak_days", "gauge", "Consecutive posting days ending today or yesterday.", stats.CurrentStreak},
+		{"hugo_calendar_longest_streak_days", "gauge", "Longest run of consecutive posting days.", stats.LongestStreak},
+	}
+	for _, metric := range metrics {
+		// Counter samples carry a _total suffix on the family name
+		sample := metric.name
+		if metric.kind == "counter" {
+			sample += "_total"
+		}
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n",
+			metric.name, metric.help, metric.name, metric.kind, sample, metric.value)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "# EOF")
+	return err
+}
+
+// exportStatsd sends one DogStatsD gauge per active day to the given UDP
+// address, tagging each with its date.
+func exportStatsd(addr string, postCounts map[string]int) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		count := postCounts[dateKey]
+		if count == 0 {
+			continue
+		}
+		_, err := fmt.Fprintf(conn, "hugo_calendar.posts_per_day:%d|g|#date:%s", count, dateKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
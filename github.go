@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// writeGitHubActions reports on the displayed months for a GitHub Actions
+// run. Annotations go to w, normally stdout, where the runner picks them up,
+// and a Markdown calendar is appended to the step summary file when the
+// runner provides one.
+func writeGitHubActions(w io.Writer, config *Config, postCounts map[string]int) error {
+	months, err := calendarMonths(postCounts, config.Month, config.calendarOptions())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	streaks := computeStreaks(postCounts, now, config.RestDays)
+	if streaks.Current > 0 && streaks.Current%config.NotifyEvery == 0 {
+		fmt.Fprintf(w, "::notice::🔥 %d-day posting streak!\n", streaks.Current)
+	}
+
+	if config.Goal > 0 {
+		thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for _, month := range months {
+			// The current month can still reach its goal
+			if !month.Before(thisMonth) {
+				continue
+			}
+			if monthPosts := countMonthPosts(postCounts, month); monthPosts < config.Goal {
+				fmt.Fprintf(w, "::warning::Missed goal in %s: %d/%d posts\n",
+					month.Format("January 2006"), monthPosts, config.Goal)
+			}
+		}
+	}
+
+	summaryPath := os.Getenv("GITHUB_STEP_SUMMARY")
+	if summaryPath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, month := range months {
+		if _, err := file.WriteString(markdownCalendar(month, postCounts)); err != nil {
+			return err
+		}
+	}
+
+	return file.Close()
+}
+
+// markdownCalendar renders month as a Markdown table with days that have
+// posts in bold.
+func markdownCalendar(month time.Time, postCounts map[string]int) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "### %s\n\n", month.Format("January 2006"))
+	sb.WriteString("| Su | Mo | Tu | We | Th | Fr | Sa |\n")
+	sb.WriteString("|----|----|----|----|----|----|----|\n")
+
+	firstDay := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	daysInMonth := firstDay.AddDate(0, 1, -1).Day()
+
+	cells := make([]string, int(firstDay.Weekday()))
+	for day := 1; day <= daysInMonth; day++ {
+		dateKey := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		if postCounts[dateKey] > 0 {
+			cells = append(cells, fmt.Sprintf("**%d**", day))
+		} else {
+			cells = append(cells, fmt.Sprintf("%d", day))
+		}
+	}
+	for len(cells)%7 != 0 {
+		cells = append(cells, "")
+	}
+
+	for week := 0; week < len(cells); week += 7 {
+		fmt.Fprintf(&sb, "| %s |\n", strings.Join(cells[week:week+7], " | "))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PeriodCount is the number of posts in one week or month.
+type PeriodCount struct {
+	Period string    `json:"period"` // YYYY-Www or YYYY-MM
+	Start  time.Time `json:"-"`
+	Posts  int       `json:"posts"`
+}
+
+// period describes a span of time that posts can be grouped by.
+type period struct {
+	start func(date time.Time) time.Time  // Start of the period containing date
+	next  func(start time.Time) time.Time // Start of the following period
+	label func(start time.Time) string
+}
+
+var (
+	// Weeks start on Monday and are labeled with their ISO week
+	weekPeriod = period{
+		start: func(date time.Time) time.Time { return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7)) },
+		next:  func(start time.Time) time.Time { return start.AddDate(0, 0, 7) },
+		label: func(start time.Time) string {
+			year, week := start.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		},
+	}
+	monthPeriod = period{
+		start: func(date time.Time) time.Time { return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC) },
+		next:  func(start time.Time) time.Time { return start.AddDate(0, 1, 0) },
+		label: func(start time.Time) string { return start.Format("2006-01") },
+	}
+)
+
+// groupByPeriod totals postCounts by period p, for every period from the
+// first post to the last between from and to. Periods without posts are
+// included so gaps show up.
+func groupByPeriod(postCounts map[string]int, from, to time.Time, p period) []PeriodCount {
+	var counts []PeriodCount
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil || postCounts[dateKey] == 0 {
+			continue
+		}
+		if (!from.IsZero() && date.Before(from)) || (!to.IsZero() && !date.Before(to)) {
+			continue
+		}
+
+		start := p.start(date)
+		if len(counts) > 0 {
+			for gap := p.next(counts[len(counts)-1].Start); gap.Before(start); gap = p.next(gap) {
+				counts = append(counts, PeriodCount{Period: p.label(gap), Start: gap})
+			}
+		}
+		if len(counts) == 0 || !counts[len(counts)-1].Start.Equal(start) {
+			counts = append(counts, PeriodCount{Period: p.label(start), Start: start})
+		}
+		counts[len(counts)-1].Posts += postCounts[dateKey]
+	}
+	return counts
+}
+
+// writePeriodCounts writes counts in the given output format: a bar chart
+// for calendar, or json or csv. If target is non-nil, bars are colored by
+// how they compare to it.
+func writePeriodCounts(w io.Writer, format string, counts []PeriodCount, target func(PeriodCount) int) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if counts == nil {
+			counts = []PeriodCount{}
+		}
+		return encoder.Encode(counts)
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"period", "posts"})
+		for _, count := range counts {
+			writer.Write([]string{count.Period, strconv.Itoa(count.Posts)})
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	printPeriodCounts(w, counts, target)
+	return nil
+}
+
+// printPeriodCounts writes a bar per period with its post count.
+func printPeriodCounts(w io.Writer, counts []PeriodCount, target func(PeriodCount) int) {
+	if len(counts) == 0 {
+		fmt.Fprintln(w, "No posts in the displayed range.")
+		return
+	}
+
+	longest := 0
+	for _, count := range counts {
+		longest = max(longest, count.Posts)
+	}
+
+	for _, count := range counts {
+		bar := strings.Repeat("█", count.Posts) + strings.Repeat(" ", longest-count.Posts)
+		if target != nil {
+			bar = cadenceColor(count.Posts, target(count)).Sprint(bar)
+		}
+		noun := "posts"
+		if count.Posts == 1 {
+			noun = "post"
+		}
+		fmt.Fprintf(w, "%-8s  %s  %d %s\n", count.Period, bar, count.Posts, noun)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Bucket counts the posts whose word count falls in [Min, Max). Max is
+// zero for the open-ended last bucket.
+type Bucket struct {
+	Min, Max int
+	Posts    int
+}
+
+// Label returns the bucket's range, such as "200–500" or "2000+".
+func (b Bucket) Label() string {
+	if b.Max == 0 {
+		return fmt.Sprintf("%d+", b.Min)
+	}
+	return fmt.Sprintf("%d–%d", b.Min, b.Max)
+}
+
+// postLengthBounds are the lower bounds of the post length buckets.
+var postLengthBounds = []int{0, 200, 500, 1000, 2000}
+
+// bucketizePostLengths counts posts into buckets by word count.
+func bucketizePostLengths(posts []PostMeta) []Bucket {
+	buckets := make([]Bucket, len(postLengthBounds))
+	for i, bound := range postLengthBounds {
+		buckets[i].Min = bound
+		if i+1 < len(postLengthBounds) {
+			buckets[i].Max = postLengthBounds[i+1]
+		}
+	}
+	for _, post := range posts {
+		for i := len(buckets) - 1; i >= 0; i-- {
+			if post.WordCount >= buckets[i].Min {
+				buckets[i].Posts++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// printPostLengthHistogram writes a horizontal bar for each bucket, one
+// block per post.
+func printPostLengthHistogram(w io.Writer, buckets []Bucket) {
+	width := 0
+	for _, bucket := range buckets {
+		width = max(width, len([]rune(bucket.Label())))
+	}
+	for _, bucket := range buckets {
+		label := bucket.Label()
+		padding := strings.Repeat(" ", width-len([]rune(label)))
+		fmt.Fprintf(w, "%s%s words  %s %d\n", padding, label, strings.Repeat("█", bucket.Posts), bucket.Posts)
+	}
+}
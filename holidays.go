@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// holidayRule computes the date of one public holiday in a given year.
+type holidayRule struct {
+	name string
+	date func(year int) time.Time
+}
+
+func fixedDate(month time.Month, day int) func(int) time.Time {
+	return func(year int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nthWeekday returns the nth weekday of month, counting from the end of
+// the month when n is negative (-1 is the last one).
+func nthWeekday(month time.Month, weekday time.Weekday, n int) func(int) time.Time {
+	return func(year int) time.Time {
+		if n < 0 {
+			last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+			return last.AddDate(0, 0, -offset+(n+1)*7)
+		}
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+(n-1)*7)
+	}
+}
+
+// weekdayBefore returns the last weekday strictly before month/day, like
+// Canada's Victoria Day on the Monday before May 25.
+func weekdayBefore(month time.Month, day int, weekday time.Weekday) func(int) time.Time {
+	return func(year int) time.Time {
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+		offset := (int(date.Weekday()) - int(weekday) + 7) % 7
+		return date.AddDate(0, 0, -offset)
+	}
+}
+
+// easterOffset returns the date days after Easter Sunday.
+func easterOffset(days int) func(int) time.Time {
+	return func(year int) time.Time {
+		return easterSunday(year).AddDate(0, 0, days)
+	}
+}
+
+// easterSunday computes the date of Western Easter with the anonymous
+// Gregorian (Meeus/Jones/Butcher) algorithm.
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// holidayRules holds the national public holidays of each supported
+// country. Regional holidays and substitute days are not included.
+var holidayRules = map[string][]holidayRule{
+	"US": {
+		{"New Year's Day", fixedDate(time.January, 1)},
+		{"Martin Luther King Jr. Day", nthWeekday(time.January, time.Monday, 3)},
+		{"Presidents' Day", nthWeekday(time.February, time.Monday, 3)},
+		{"Memorial Day", nthWeekday(time.May, time.Monday, -1)},
+		{"Juneteenth", fixedDate(time.June, 19)},
+		{"Independence Day", fixedDate(time.July, 4)},
+		{"Labor Day", nthWeekday(time.September, time.Monday, 1)},
+		{"Columbus Day", nthWeekday(time.October, time.Monday, 2)},
+		{"Veterans Day", fixedDate(time.November, 11)},
+		{"Thanksgiving", nthWeekday(time.November, time.Thursday, 4)},
+		{"Christmas Day", fixedDate(time.December, 25)},
+	},
+	"GB": {
+		{"New Year's Day", fixedDate(time.January, 1)},
+		{"Good Friday", easterOffset(-2)},
+		{"Easter Monday", easterOffset(1)},
+		{"Early May Bank Holiday", nthWeekday(time.May, time.Monday, 1)},
+		{"Spring Bank Holiday", nthWeekday(time.May, time.Monday, -1)},
+		{"Summer Bank Holiday", nthWeekday(time.August, time.Monday, -1)},
+		{"Christmas Day", fixedDate(time.December, 25)},
+		{"Boxing Day", fixedDate(time.December, 26)},
+	},
+	"DE": {
+		{"New Year's Day", fixedDate(time.January, 1)},
+		{"Good Friday", easterOffset(-2)},
+		{"Easter Monday", easterOffset(1)},
+		{"Labour Day", fixedDate(time.May, 1)},
+		{"Ascension Day", easterOffset(39)},
+		{"Whit Monday", easterOffset(50)},
+		{"German Unity Day", fixedDate(time.October, 3)},
+		{"Christmas Day", fixedDate(time.December, 25)},
+		{"Second Day of Christmas", fixedDate(time.December, 26)},
+	},
+	"FR": {
+		{"New Year's Day", fixedDate(time.January, 1)},
+		{"Easter Monday", easterOffset(1)},
+		{"Labour Day", fixedDate(time.May, 1)},
+		{"Victory in Europe Day", fixedDate(time.May, 8)},
+		{"Ascension Day", easterOffset(39)},
+		{"Whit Monday", easterOffset(50)},
+		{"Bastille Day", fixedDate(time.July, 14)},
+		{"Assumption of Mary", fixedDate(time.August, 15)},
+		{"All Saints' Day", fixedDate(time.November, 1)},
+		{"Armistice Day", fixedDate(time.November, 11)},
+		{"Christmas Day", fixedDate(time.December, 25)},
+	},
+	"CA": {
+		{"New Year's Day", fixedDate(time.January, 1)},
+		{"Good Friday", easterOffset(-2)},
+		{"Victoria Day", weekdayBefore(time.May, 25, time.Monday)},
+		{"Canada Day", fixedDate(time.July, 1)},
+		{"Labour Day", nthWeekday(time.September, time.Monday, 1)},
+		{"Thanksgiving", nthWeekday(time.October, time.Monday, 2)},
+		{"Remembrance Day", fixedDate(time.November, 11)},
+		{"Christmas Day", fixedDate(time.December, 25)},
+		{"Boxing Day", fixedDate(time.December, 26)},
+	},
+}
+
+// supportedCountries returns the country codes in holidayRules, sorted.
+func supportedCountries() []string {
+	countries := make([]string, 0, len(holidayRules))
+	for country := range holidayRules {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// HolidayCalendar looks up the public holidays of a country, computing
+// each year's dates the first time they are needed.
+type HolidayCalendar struct {
+	country string
+	years   map[int]map[string]string // Year to date key to holiday name
+	custom  map[string]string         // Date key to name, from --custom-holidays
+}
+
+// newHolidayCalendar returns a calendar for a country code from
+// holidayRules, or nil if the country isn't supported.
+func newHolidayCalendar(country string) *HolidayCalendar {
+	country = strings.ToUpper(country)
+	if _, ok := holidayRules[country]; !ok {
+		return nil
+	}
+	return &HolidayCalendar{country: country, years: make(map[int]map[string]string)}
+}
+
+// Name returns the name of the holiday on date, or "" if it isn't one. A
+// nil calendar has no holidays.
+func (h *HolidayCalendar) Name(date time.Time) string {
+	if h == nil {
+		return ""
+	}
+	if name, ok := h.custom[date.Format("2006-01-02")]; ok {
+		return name
+	}
+
+	holidays, ok := h.years[date.Year()]
+	if !ok {
+		holidays = make(map[string]string)
+		for _, rule := range holidayRules[h.country] {
+			holidays[rule.date(date.Year()).Format("2006-01-02")] = rule.name
+		}
+		h.years[date.Year()] = holidays
+	}
+
+	return holidays[date.Format("2006-01-02")]
+}
+
+// withCustom returns a copy of h that also includes custom holidays. A nil
+// h gives a calendar of only the custom holidays.
+func (h *HolidayCalendar) withCustom(custom map[string]string) *HolidayCalendar {
+	calendar := &HolidayCalendar{years: make(map[int]map[string]string), custom: custom}
+	if h != nil {
+		calendar.country = h.country
+	}
+	return calendar
+}
+
+type customHoliday struct {
+	Date string `yaml:"date"`
+	Name string `yaml:"name"`
+}
+
+// loadCustomHolidays reads a YAML list of date/name entries from a file, or
+// from an http(s) URL, and returns them keyed by date.
+func loadCustomHolidays(source string) (map[string]string, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetchURL(source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []customHoliday
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	holidays := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date '%s'", entry.Date)
+		}
+		holidays[date.Format("2006-01-02")] = entry.Name
+	}
+	return holidays, nil
+}
+
+// fetchURL returns the body of a GET request to url.
+func fetchURL(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// warnDuplicateTitles writes a warning to w for every title shared by more
+// than one post and reports whether any were found. Titles are compared ignoring
+// case and surrounding whitespace.
+func warnDuplicateTitles(w io.Writer, postMetas map[string][]PostMeta, config *Config) bool {
+	titlePaths := make(map[string][]string)
+	titles := make(map[string]string) // Normalized title to first seen spelling
+	for _, post := range sortedPosts(postMetas) {
+		key := strings.ToLower(strings.TrimSpace(post.Title))
+		if key == "" {
+			continue
+		}
+		if _, ok := titles[key]; !ok {
+			titles[key] = post.Title
+		}
+		titlePaths[key] = append(titlePaths[key], post.Path)
+	}
+
+	keys := make([]string, 0, len(titlePaths))
+	for key, paths := range titlePaths {
+		if len(paths) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		title := titles[key]
+		if config.NoTitle {
+			title = "[redacted]"
+		}
+		fmt.Fprintf(w, "Warning: Duplicate title %q used by %d posts:\n", title, len(titlePaths[key]))
+		for _, path := range titlePaths[key] {
+			fmt.Fprintf(w, "  %s\n", config.displayPath(path))
+		}
+	}
+
+	return len(keys) > 0
+}
+
+// warnFutureDates writes a warning to w for every post dated more than
+// days after now, which usually means a typo in the year, and reports
+// whether any were found.
+func warnFutureDates(w io.Writer, postMetas map[string][]PostMeta, days int, now time.Time, config *Config) bool {
+	limit := now.AddDate(0, 0, days)
+	found := false
+	for _, post := range sortedPosts(postMetas) {
+		if post.Date.After(limit) {
+			fmt.Fprintf(w, "Warning: %s is dated %s, more than %d days in the future\n",
+				config.displayPath(post.Path), post.Date.Format("2006-01-02"), days)
+			found = true
+		}
+	}
+	return found
+}
+
+// firstCommitTime returns the author date of the oldest commit in the
+// project's git repository.
+func firstCommitTime(projectPath string) (time.Time, error) {
+	output, err := exec.Command("git", "-C", projectPath, "log", "--reverse", "--format=%aI").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	firstLine, _, _ := strings.Cut(string(output), "\n")
+	return time.Parse(time.RFC3339, strings.TrimSpace(firstLine))
+}
+
+// warnPastDates writes a warning to w for every post dated more than 30 days
+// before the project's first commit, which usually means the date was
+// never set, and reports whether any were found. Projects that aren't git
+// repositories are skipped silently.
+func warnPastDates(w io.Writer, postMetas map[string][]PostMeta, config *Config) bool {
+	firstCommit, err := firstCommitTime(config.ProjectPath)
+	if err != nil {
+		logVerbose("Skipping past date check, no git history: %v", err)
+		return false
+	}
+
+	limit := firstCommit.AddDate(0, 0, -30)
+	found := false
+	for _, post := range sortedPosts(postMetas) {
+		if post.Date.Before(limit) {
+			fmt.Fprintf(w, "Warning: %s is dated %s, before the first commit on %s\n",
+				config.displayPath(post.Path), post.Date.Format("2006-01-02"), firstCommit.Format("2006-01-02"))
+			found = true
+		}
+	}
+	return found
+}
+
+// validatePosts checks every post for the front matter a published post
+// needs, reporting posts whose front matter couldn't be parsed as well, and
+// writes one line per problem to w unless --quiet is set. It returns the
+// process exit code: 0 when every post passes and 2 otherwise.
+func validatePosts(w io.Writer, postMetas map[string][]PostMeta, config *Config) int {
+	failed := false
+	for _, post := range sortedPosts(postMetas) {
+		var problems []string
+		if post.ParseError != "" {
+			problems = append(problems, "invalid front matter: "+post.ParseError)
+		} else {
+			if post.Date.IsZero() {
+				problems = append(problems, "missing date")
+			}
+			if strings.TrimSpace(post.Title) == "" {
+				problems = append(problems, "missing title")
+			}
+			if config.RequireTags && len(post.Tags) == 0 {
+				problems = append(problems, "missing tags")
+			}
+		}
+
+		if len(problems) > 0 {
+			failed = true
+			if !config.Quiet {
+				fmt.Fprintf(w, "%s: %s\n", config.displayPath(post.Path), strings.Join(problems, ", "))
+			}
+		}
+	}
+
+	if failed {
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// printTitleList writes one line per post with its date and title. With
+// --month only posts from that month are listed. Titles wider than --wrap
+// (or the terminal) continue on indented lines under the title column.
+func printTitleList(w io.Writer, postMetas map[string][]PostMeta, config *Config) {
+	// Continuation lines line up with the title after "YYYY-MM-DD  "
+	const indent = "            "
+
+	width := config.Wrap
+	if width == 0 {
+		width = getTerminalWidth()
+	}
+
+	for _, post := range sortedPosts(postMetas) {
+		if config.Month != nil && !strings.HasPrefix(post.Date.Format("2006-01-02"), *config.Month) {
+			continue
+		}
+
+		title := post.Title
+		if config.NoTitle {
+			title = "[redacted]"
+		}
+		titleLines := wrapText(title, width-len(indent))
+
+		metadata := ""
+		if len(post.Series) > 0 {
+			metadata += fmt.Sprintf("  [%s]", strings.Join(post.Series, ", "))
+		}
+		if config.Readability {
+			metadata += fmt.Sprintf("  (readability %.1f)", post.ReadabilityScore)
+		}
+		for _, name := range config.ExtractFields {
+			if value, ok := post.ExtraFields[name]; ok {
+				metadata += fmt.Sprintf("  %s=%s", name, value)
+			}
+		}
+
+		if config.PostURLBase != "" {
+			if url, err := postURL(config, post); err == nil {
+				if color.NoColor {
+					metadata += "  " + url
+				} else {
+					for i, line := range titleLines {
+						titleLines[i] = hyperlink(url, line)
+					}
+				}
+			}
+		}
+
+		// Metadata follows the end of the title and is never wrapped
+		titleLines[len(titleLines)-1] += metadata
+
+		fmt.Fprintf(w, "%s  %s\n", post.Date.Format("2006-01-02"), titleLines[0])
+		for _, line := range titleLines[1:] {
+			fmt.Fprintf(w, "%s%s\n", indent, line)
+		}
+	}
+}
+
+// wrapText splits text into lines of at most width characters, breaking
+// between words. A word longer than width gets a line of its own. There is
+// always at least one line, even for empty text.
+func wrapText(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+// postURL builds the public URL of a post from --post-url-base and the
+// post's directory relative to its content/, which is how Hugo lays out
+// page bundles by default.
+func postURL(config *Config, post PostMeta) (string, error) {
+	relPath, err := filepath.Rel(post.ContentRoot, filepath.Dir(post.Path))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(config.PostURLBase, "/") + "/" + filepath.ToSlash(relPath) + "/", nil
+}
+
+// hyperlink wraps text in an OSC 8 escape sequence so terminals that
+// support it render a clickable link.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// printSeriesList writes every series with its number of posts, most
+// popular first.
+func printSeriesList(w io.Writer, postMetas map[string][]PostMeta) {
+	seriesCounts := countTerms(postMetas, func(meta PostMeta) []string { return meta.Series })
+	if len(seriesCounts) == 0 {
+		fmt.Fprintln(w, "No series found.")
+		return
+	}
+	printTermCounts(w, seriesCounts)
+}
+
+// printTagList writes every tag with its number of posts, most popular
+// first.
+func printTagList(w io.Writer, postMetas map[string][]PostMeta) {
+	tagCounts := countTerms(postMetas, func(meta PostMeta) []string { return meta.Tags })
+	if len(tagCounts) == 0 {
+		fmt.Fprintln(w, "No tags found.")
+		return
+	}
+	printTermCounts(w, tagCounts)
+}
+
+// printAbandonedTags writes the tags used by fewer than minUses posts,
+// with the date each was last used, most recently used first.
+func printAbandonedTags(w io.Writer, postMetas map[string][]PostMeta, minUses int) {
+	tagCounts := countTerms(postMetas, func(meta PostMeta) []string { return meta.Tags })
+	lastUsed := make(map[string]string)
+	for dateKey, metas := range postMetas {
+		for _, meta := range metas {
+			for _, tag := range meta.Tags {
+				if dateKey > lastUsed[tag] {
+					lastUsed[tag] = dateKey
+				}
+			}
+		}
+	}
+
+	var tags []string
+	for tag, count := range tagCounts {
+		if count < minUses {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		fmt.Fprintf(w, "No tags used fewer than %d times.\n", minUses)
+		return
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if lastUsed[tags[i]] != lastUsed[tags[j]] {
+			return lastUsed[tags[i]] > lastUsed[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+
+	for _, tag := range tags {
+		fmt.Fprintf(w, "%s  %4d  %s\n", lastUsed[tag], tagCounts[tag], tag)
+	}
+}
+
+// countTerms counts how many posts use each taxonomy term returned by
+// terms.
+func countTerms(postMetas map[string][]PostMeta, terms func(PostMeta) []string) map[string]int {
+	termCounts := make(map[string]int)
+	for _, metas := range postMetas {
+		for _, meta := range metas {
+			for _, term := range terms(meta) {
+				termCounts[term]++
+			}
+		}
+	}
+	return termCounts
+}
+
+// sortedTerms returns the keys of termCounts ordered by count, highest
+// first, then by name.
+func sortedTerms(termCounts map[string]int) []string {
+	names := make([]string, 0, len(termCounts))
+	for name := range termCounts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if termCounts[names[i]] != termCounts[names[j]] {
+			return termCounts[names[i]] > termCounts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func printTermCounts(w io.Writer, termCounts map[string]int) {
+	for _, name := range sortedTerms(termCounts) {
+		fmt.Fprintf(w, "%4d  %s\n", termCounts[name], name)
+	}
+}
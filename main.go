@@ -3,21 +3,83 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
-	"syscall"
 	"time"
-	"unsafe"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
+// verboseOutput is where logVerbose writes, run's stderr with --verbose and
+// nil otherwise
+var verboseOutput io.Writer
+
 type PostFrontMatter struct {
 	Title string    `yaml:"title"`
 	Date  time.Time `yaml:"date"`
 	Draft bool      `yaml:"draft"`
+
+	// Aliases are old URLs that redirect to this post
+	Aliases []string `yaml:"aliases"`
+	Series  []string `yaml:"series"`
+
+	Tags     []string `yaml:"tags"`
+	Keywords []string `yaml:"keywords"` // Some themes use these instead of tags
+
+	// Some themes nest taxonomies under params
+	Params struct {
+		Tags       []string `yaml:"tags"`
+		Categories []string `yaml:"categories"`
+	} `yaml:"params"`
+
+	Featured bool   `yaml:"featured"`
+	Type     string `yaml:"type"` // Overrides the content type Hugo infers from the section
+	Layout   string `yaml:"layout"`
+
+	// Fields holds every front matter key, including custom ones that
+	// themes define and the typed fields above don't cover
+	Fields map[string]interface{} `yaml:"-"`
+}
+
+// PostMeta describes a single post that passed all filters.
+type PostMeta struct {
+	Path       string    `json:"path"`
+	Section    string    `json:"section,omitempty"` // First directory under content/
+	Title      string    `json:"title"`
+	Author     string    `json:"author,omitempty"`
+	Date       time.Time `json:"date"`
+	Aliases    []string  `json:"aliases,omitempty"`
+	Series     []string  `json:"series,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Incomplete bool      `json:"incomplete,omitempty"` // Missing required front matter such as tags
+	Featured   bool      `json:"featured,omitempty"`
+	Type       string    `json:"type,omitempty"`
+	Layout     string    `json:"layout,omitempty"`
+	ImageCount int       `json:"image_count,omitempty"`
+	LinkCount  int       `json:"link_count,omitempty"`
+	WordCount  int       `json:"word_count,omitempty"`
+
+	ContentHash string `json:"content_hash,omitempty"` // SHA-256 of the body, with --content-hash
+
+	ContentRoot string `json:"-"` // The content/ (or public/) directory the post was read from
+	ParseError  string `json:"-"` // Why the front matter couldn't be read, with --validate
+
+	CodeBlockCount       int `json:"code_block_count,omitempty"`
+	TaggedCodeBlockCount int `json:"tagged_code_block_count,omitempty"` // Blocks that name a language
+
+	HeadingCounts [6]int `json:"heading_counts,omitzero"` // H1 through H6
+
+	ReadabilityScore float64 `json:"readability_score,omitempty"` // Flesch reading ease
+
+	ExtraFields map[string]string `json:"extra_fields,omitempty"` // Values of --extract-field fields
 }
 
 type PostCount struct {
@@ -25,166 +87,1911 @@ type PostCount struct {
 	Count int
 }
 
-type Config struct {
-	ProjectPath string
-	FilterText  string
-	ShowCounts  bool
-	Month       *string // YYYY-MM format, nil means all months
-}
+type Config struct {
+	ProjectPath       string           `yaml:"project-path"`
+	FilterText        string           `yaml:"filter-text"`
+	ShowCounts        bool             `yaml:"show-counts"`
+	Month             *string          `yaml:"month"`       // YYYY-MM format, nil means all months
+	InfluxFile        string           `yaml:"influx-file"` // Write InfluxDB line protocol to this file if set
+	StatsdAddr        string           `yaml:"statsd-addr"` // Send StatsD gauges to this UDP address if set
+	Notify            bool             `yaml:"notify"`
+	NotifyEvery       int              `yaml:"notify-every"` // Streak milestone interval in days for --notify
+	WebhookURL        string           `yaml:"webhook-url"`
+	WebhookSecret     string           `yaml:"webhook-secret"` // Used to sign webhook payloads
+	Strict            bool             `yaml:"strict"`         // Treat warnings as errors
+	SlackWebhook      string           `yaml:"slack-webhook"`
+	Goal              int              `yaml:"goal"` // Monthly post goal, 0 means no goal
+	GitHubActions     bool             `yaml:"github-actions"`
+	NoPager           bool             `yaml:"no-pager"` // Never pause between calendar rows
+	Verbose           bool             `yaml:"verbose"`
+	CountImages       bool             `yaml:"count-images"` // Count images per post, shown instead of posts with --counts
+	Output            string           `yaml:"output"`       // "calendar" or "json"
+	CountLinks        bool             `yaml:"count-links"`
+	ShowStats         bool             `yaml:"show-stats"` // Print a statistics summary after the calendar
+	CountCode         bool             `yaml:"count-code"` // Count fenced code blocks per post
+	CountHeadings     bool             `yaml:"count-headings"`
+	Readability       bool             `yaml:"readability"` // Compute a reading ease score per post
+	TitleList         bool             `yaml:"title-list"`  // List post titles after the calendar
+	ExtractFields     []string         `yaml:"extract-fields"`
+	FieldFilters      []FieldFilter    `yaml:"field-filters"` // All must match for a post to be counted
+	HasAliases        bool             `yaml:"has-aliases"`   // Only count posts with aliases
+	Series            string           `yaml:"series"`        // Only count posts in this series
+	ListSeries        bool             `yaml:"list-series"`   // List series with post counts instead of the calendar
+	FeaturedOnly      bool             `yaml:"featured-only"`
+	Types             []string         `yaml:"types"`         // Only count posts with one of these types
+	Layouts           []string         `yaml:"layouts"`       // Only count posts with one of these layouts
+	DayOfMonth        int              `yaml:"day-of-month"`  // Only count posts on this day of the month, zero means any
+	NoTitle           bool             `yaml:"no-title"`      // Redact post titles in all output
+	Anonymize         bool             `yaml:"anonymize"`     // Replace file paths with hashes in all output
+	PostURLBase       string           `yaml:"post-url-base"` // Site URL used to link titles in the title list
+	NoColor           bool             `yaml:"no-color"`
+	DedupeTitles      bool             `yaml:"dedupe-titles"` // Warn about posts sharing a title
+	CheckFuture       bool             `yaml:"check-future"`  // Warn about posts dated more than FutureDays ahead
+	FutureDays        int              `yaml:"future-days"`
+	CheckPast         bool             `yaml:"check-past"`   // Warn about posts dated well before the first git commit
+	Validate          bool             `yaml:"validate"`     // Check front matter instead of rendering
+	RequireTags       bool             `yaml:"require-tags"` // Posts need at least one tag to be complete
+	ExcludeIncomplete bool             `yaml:"exclude-incomplete"`
+	Quiet             bool             `yaml:"quiet"`
+	KeywordsAsTags    bool             `yaml:"keywords-as-tags"`  // Use keywords as tags for posts without tags
+	FilterTags        []string         `yaml:"filter-tags"`       // Only count posts with one of these tags
+	ListTags          bool             `yaml:"list-tags"`         // List tags with post counts instead of the calendar
+	OutputEncoding    string           `yaml:"output-encoding"`   // Empty means UTF-8
+	Wrap              int              `yaml:"wrap"`              // Title list width, 0 means the terminal width
+	PublicPath        string           `yaml:"public-path"`       // Read dates from generated HTML instead of content/
+	IgnoreSections    []string         `yaml:"ignore-sections"`   // Top-level directories to skip entirely
+	From              time.Time        `yaml:"from,omitempty"`    // First month to show, zero means no limit
+	To                time.Time        `yaml:"to,omitempty"`      // Last month to show, zero means no limit
+	Week              string           `yaml:"week,omitempty"`    // ISO week to show, YYYY-Www format
+	WeekStart         time.Time        `yaml:"-"`                 // Monday of Week, set when it is validated
+	Quarter           int              `yaml:"-"`                 // Show this quarter (1-4) of --year or the current year
+	FiscalYear        int              `yaml:"fiscal-year"`       // Month (1-12) that --year starts in, zero means January
+	ShowEmptyMonths   bool             `yaml:"show-empty-months"` // Show every month in the range, even without posts
+	OnlyWithPosts     bool             `yaml:"only-with-posts"`   // Skip months without posts, the default with --year
+	Recent            int              `yaml:"recent"`            // Show only this many of the latest months with posts
+	FirstPostOfDay    bool             `yaml:"first-post-of-day"` // Count only one post per day
+	GroupByWeek       bool             `yaml:"group-by-week"`     // List posts per ISO week instead of the calendar
+	GroupByMonth      bool             `yaml:"group-by-month"`    // List posts per month instead of the calendar
+	Correlation       bool             `yaml:"correlation"`       // Rank tags by how busy the days using them are
+	ContentHash       bool             `yaml:"content-hash"`      // Report posts whose body changed since the last run
+	WordCountGoal     int              `yaml:"word-count-goal"`   // Words a month to aim for in --stats, zero for none
+	Holidays          *HolidayCalendar `yaml:"holidays"`          // Public holidays to mark, nil for none
+	RestDays          RestDays         `yaml:"rest-days"`         // Days skipped when counting streaks
+	CustomHolidays    string           `yaml:"custom-holidays"`   // YAML file or URL of extra holidays
+	HighlightDates    string           `yaml:"highlight-dates"`   // File of dates to highlight on the calendar
+	HighlightDays     map[string]bool  `yaml:"-"`                 // Dates read from HighlightDates
+	ExcludeDates      string           `yaml:"exclude-dates"`     // File of dates whose posts aren't counted
+	ExcludeDays       map[string]bool  `yaml:"-"`                 // Dates read from ExcludeDates
+	Cadence           *Cadence         `yaml:"cadence"`           // Posting target for --stats, nil for none
+	BestTime          bool             `yaml:"best-time"`         // Print the weekday and day of month with most posts
+	MovingAverage     int              `yaml:"moving-average"`    // Days in the --stats moving average, 0 for none
+	PercentileDays    int              `yaml:"percentile-days"`   // Highlight days above this percentile, 0 for none
+	IgnoreBundles     bool             `yaml:"ignore-bundles"`    // Skip posts whose directory holds resources
+	ApplyCascade      bool             `yaml:"apply-cascade"`     // Inherit front matter from _index.md cascades
+	CountBySection    bool             `yaml:"count-by-section"`  // Read every content section and break posts down by it
+	ShowPaths         bool             `yaml:"show-paths"`        // Print posts as a directory tree instead of a calendar
+	ConfigDump        bool             `yaml:"-"`                 // Print the resolved configuration and exit
+	MigrateConfig     string           `yaml:"-"`                 // Save the resolved configuration to this file and exit
+	SelfUpdate        bool             `yaml:"-"`                 // Replace the binary with the latest release and exit
+	CheckUpdate       bool             `yaml:"-"`                 // Report whether a newer release exists and exit
+	Metrics           bool             `yaml:"metrics"`           // Write OpenMetrics text after rendering
+	MetricsFile       string           `yaml:"metrics-file"`      // Write the metrics here instead of stderr
+	Elapsed           bool             `yaml:"elapsed"`           // Print the time taken by each phase to stderr
+	FilterRegexes     []*regexp.Regexp `yaml:"-"`                 // Exclude posts whose body matches any of these
+	NoClone           bool             `yaml:"no-clone"`          // Treat URL project paths as local paths
+	GitLab            bool             `yaml:"gitlab"`            // Expand OWNER/REPO paths to GitLab rather than GitHub
+	Combine           bool             `yaml:"-"`                 // Sum the posts of several projects into one calendar
+	CombinePaths      []string         `yaml:"-"`                 // Project paths after the first, for --combine
+	NullSeparated     bool             `yaml:"-"`                 // Read NUL-separated project paths from stdin
+	PrintConfigPath   bool             `yaml:"-"`                 // Print which config files were read to stderr
+	ConfigFiles       []string         `yaml:"-"`                 // Config files read, in order
+	Given             map[string]bool  `yaml:"-"`                 // Long names of the range flags given on the command line
+	ASCII             bool             `yaml:"ascii"`             // Draw trees and calendar marks with plain ASCII characters
+
+	// Author statistics
+	CountPerAuthor  bool `yaml:"count-posts-per-author"` // Add a table of posts per author to --stats
+	TopAuthors      int  `yaml:"top-authors"`            // Authors shown by --count-posts-per-author
+	AuthorBreakdown bool `yaml:"author-breakdown"`       // Draw a calendar per author
+	AuthorLimit     int  `yaml:"author-limit"`           // Authors drawn by --author-breakdown, zero for all
+
+	TagBreakdown bool `yaml:"tag-breakdown"` // Draw a calendar per tag
+	TagLimit     int  `yaml:"tag-limit"`     // Tags drawn by --tag-breakdown
+
+	LengthHistogram bool `yaml:"post-length-histogram"` // Chart posts by word count
+	UniqueTags      bool `yaml:"unique-tags-per-month"` // Add distinct tags per month to --stats
+	NewTags         bool `yaml:"new-tags-per-month"`    // Add first-time tags per month to --stats
+	AbandonedTags   bool `yaml:"abandoned-tags"`        // List rarely used tags instead of the calendar
+	MinTagUses      int  `yaml:"min-tag-uses"`          // Tags with fewer posts count as abandoned
+}
+
+// CalendarOptions controls how calendar grids are rendered.
+type CalendarOptions struct {
+	ShowCounts      bool
+	Paginate        bool            // Pause between rows taller than the terminal
+	DimDays         map[string]bool // Days whose posts are all incomplete
+	BusyDays        map[string]bool // Unusually active days, from --percentile-days
+	From            time.Time       // First month to show, zero means no limit
+	To              time.Time       // Last month to show, zero means no limit
+	Week            time.Time       // Monday of the ISO week to show, zero for whole months
+	Columns         int             // Calendars per row, zero to fit the terminal
+	Anniversary     string          // Date of the first post, marked with a cake
+	ASCII           bool            // Mark the anniversary with * rather than a cake
+	ShowEmptyMonths bool            // Fill the whole From-To range, marking empty months
+	OnlyWithPosts   bool            // Leave out months without posts
+	Recent          int             // Keep only the last this many months with posts, zero for all
+	DayOfMonth      int             // Day of the month to ring, zero for none
+	Highlight       map[string]bool // Days from --highlight-dates
+	Holidays        *HolidayCalendar
+	Colors          *ColorScheme
+	RestDays        RestDays // Dimmed since they don't count against streaks
+}
+
+// calendarOptions returns the options for the calendar that follow
+// directly from the command line.
+func (c *Config) calendarOptions() CalendarOptions {
+	opts := CalendarOptions{
+		ShowCounts:      c.ShowCounts,
+		From:            c.From,
+		To:              c.To,
+		Week:            c.WeekStart,
+		ShowEmptyMonths: c.ShowEmptyMonths,
+		OnlyWithPosts:   c.OnlyWithPosts,
+		Recent:          c.Recent,
+		DayOfMonth:      c.DayOfMonth,
+		Highlight:       c.HighlightDays,
+		Holidays:        c.Holidays,
+		RestDays:        c.RestDays,
+		ASCII:           c.ASCII || !isUTF8(c.OutputEncoding), // The cake would become a one column '?'
+	}
+	// A quarter is always laid out as one row of three months
+	if c.Quarter > 0 {
+		opts.Columns = 3
+	}
+	return opts
+}
+
+// displayPath returns path as it should appear in output, which is a short
+// stable hash when --anonymize is set.
+func (c *Config) displayPath(path string) string {
+	if c.Anonymize {
+		return shortHash(path, 8)
+	}
+	return path
+}
+
+// FieldFilter matches posts whose front matter field Name equals Value,
+// ignoring case.
+type FieldFilter struct {
+	Name  string
+	Value string
+}
+
+// parseArgs builds the configuration from the command line arguments,
+// without the program name, layered over any config files.
+func parseArgs(args []string) (*Config, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing project path")
+	}
+
+	// The flags are parsed once to find the project, then again on top of
+	// the config files so that they take precedence
+	probe, err := parseFlags(args, defaultConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	config := defaultConfig()
+	configFiles, err := loadConfigFiles(config, probe.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Months chosen on the command line replace those from the config
+	// files, rather than mixing with them or conflicting
+	for _, flag := range []string{"--month", "--year", "--from", "--to", "--week", "--quarter", "--recent"} {
+		if probe.Given[flag] {
+			config.Month, config.From, config.To, config.Week, config.Recent = nil, time.Time{}, time.Time{}, "", 0
+			break
+		}
+	}
+	if probe.Given["--show-empty-months"] || probe.Given["--only-with-posts"] {
+		config.ShowEmptyMonths, config.OnlyWithPosts = false, false
+	}
+
+	if config, err = parseFlags(args, config); err != nil {
+		return nil, err
+	}
+	config.ConfigFiles = configFiles
+	return config, nil
+}
+
+// conflictingFlags lists pairs of flags that select the same thing in
+// different ways, where one would silently win over the other.
+var conflictingFlags = [][2]string{
+	{"--month", "--year"},
+	{"--month", "--from"},
+	{"--month", "--to"},
+	{"--year", "--from"},
+	{"--year", "--to"},
+	{"--week", "--month"},
+	{"--week", "--year"},
+	{"--week", "--from"},
+	{"--week", "--to"},
+	{"--quarter", "--month"},
+	{"--quarter", "--week"},
+	{"--quarter", "--from"},
+	{"--quarter", "--to"},
+	{"--recent", "--month"},
+	{"--recent", "--week"},
+}
+
+func defaultConfig() *Config {
+	return &Config{NotifyEvery: 7, Output: "calendar", FutureDays: 365, TopAuthors: 5, TagLimit: 10, MinTagUses: 2}
+}
+
+// parseFlags applies the command line arguments to config and validates
+// the result.
+func parseFlags(args []string, config *Config) (*Config, error) {
+	given := make(map[string]bool) // Long names of the flags seen, for conflict checks
+	var paths []string             // Project paths given on the command line
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+
+		if arg == "-f" || arg == "--filter" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("filter flag requires a value")
+			}
+			config.FilterText = args[i+1]
+			i += 2
+		} else if arg == "-c" || arg == "--counts" {
+			config.ShowCounts = true
+			i++
+		} else if arg == "-m" || arg == "--month" {
+			given["--month"] = true
+			// Check if next arg exists and is not a flag; a negative
+			// offset like -3 is a value, not a flag
+			if i+1 < len(args) && (!strings.HasPrefix(args[i+1], "-") || isMonthOffset(args[i+1])) {
+				month := args[i+1]
+				config.Month = &month
+				i += 2
+			} else {
+				// No value provided, use current month
+				currentMonth := time.Now().Format("2006-01")
+				config.Month = &currentMonth
+				i++
+			}
+		} else if arg == "--export-influx" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("export-influx flag requires a file path")
+			}
+			config.InfluxFile = args[i+1]
+			i += 2
+		} else if arg == "--export-statsd" {
+			// Address is optional, default to the local StatsD agent
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				config.StatsdAddr = args[i+1]
+				i += 2
+			} else {
+				config.StatsdAddr = "localhost:8125"
+				i++
+			}
+		} else if arg == "--notify" {
+			config.Notify = true
+			i++
+		} else if arg == "--notify-every" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("notify-every flag requires a value")
+			}
+			every, err := strconv.Atoi(args[i+1])
+			if err != nil || every < 1 {
+				return nil, fmt.Errorf("invalid notify-every value '%s', expected a positive number of days", args[i+1])
+			}
+			config.NotifyEvery = every
+			i += 2
+		} else if arg == "--webhook" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("webhook flag requires a URL")
+			}
+			config.WebhookURL = args[i+1]
+			i += 2
+		} else if arg == "--webhook-secret" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("webhook-secret flag requires a value")
+			}
+			config.WebhookSecret = args[i+1]
+			i += 2
+		} else if arg == "--strict" {
+			config.Strict = true
+			i++
+		} else if arg == "--slack-webhook" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("slack-webhook flag requires a URL")
+			}
+			config.SlackWebhook = args[i+1]
+			i += 2
+		} else if arg == "--goal" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("goal flag requires a value")
+			}
+			goal, err := strconv.Atoi(args[i+1])
+			if err != nil || goal < 1 {
+				return nil, fmt.Errorf("invalid goal value '%s', expected a positive number of posts", args[i+1])
+			}
+			config.Goal = goal
+			i += 2
+		} else if arg == "--github-actions" {
+			config.GitHubActions = true
+			i++
+		} else if arg == "--no-pager" {
+			config.NoPager = true
+			i++
+		} else if arg == "-v" || arg == "--verbose" {
+			config.Verbose = true
+			i++
+		} else if arg == "--count-images" {
+			config.CountImages = true
+			i++
+		} else if arg == "--count-links" {
+			config.CountLinks = true
+			i++
+		} else if arg == "--count-code-blocks" {
+			config.CountCode = true
+			i++
+		} else if arg == "--count-headings" {
+			config.CountHeadings = true
+			i++
+		} else if arg == "--readability" {
+			config.Readability = true
+			i++
+		} else if arg == "-t" || arg == "--title-list" {
+			config.TitleList = true
+			i++
+		} else if arg == "--extract-field" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("extract-field flag requires a field name")
+			}
+			config.ExtractFields = append(config.ExtractFields, args[i+1])
+			i += 2
+		} else if arg == "--filter-field" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("filter-field flag requires a FIELD=VALUE value")
+			}
+			name, value, found := strings.Cut(args[i+1], "=")
+			if !found || name == "" {
+				return nil, fmt.Errorf("invalid filter-field value '%s', expected FIELD=VALUE", args[i+1])
+			}
+			config.FieldFilters = append(config.FieldFilters, FieldFilter{Name: name, Value: value})
+			i += 2
+		} else if arg == "--filter-has-aliases" {
+			config.HasAliases = true
+			i++
+		} else if arg == "--series" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("series flag requires a name")
+			}
+			config.Series = args[i+1]
+			i += 2
+		} else if arg == "--list-series" {
+			config.ListSeries = true
+			i++
+		} else if arg == "--featured-only" {
+			config.FeaturedOnly = true
+			i++
+		} else if arg == "--type" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("type flag requires a value")
+			}
+			config.Types = append(config.Types, args[i+1])
+			i += 2
+		} else if arg == "--layout" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("layout flag requires a value")
+			}
+			config.Layouts = append(config.Layouts, args[i+1])
+			i += 2
+		} else if arg == "--day-of-month" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("day-of-month flag requires a value")
+			}
+			day, err := strconv.Atoi(args[i+1])
+			if err != nil || day < 1 || day > 31 {
+				return nil, fmt.Errorf("invalid day of month '%s', expected 1-31", args[i+1])
+			}
+			config.DayOfMonth = day
+			i += 2
+		} else if arg == "--no-title" {
+			config.NoTitle = true
+			i++
+		} else if arg == "--anonymize" {
+			config.Anonymize = true
+			i++
+		} else if arg == "--post-url-base" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("post-url-base flag requires a URL")
+			}
+			config.PostURLBase = args[i+1]
+			i += 2
+		} else if arg == "--no-color" {
+			config.NoColor = true
+			i++
+		} else if arg == "--deduplicate-titles" {
+			config.DedupeTitles = true
+			i++
+		} else if arg == "--check-dates-in-future" {
+			config.CheckFuture = true
+			// Number of days is optional
+			if i+1 < len(args) {
+				if days, err := strconv.Atoi(args[i+1]); err == nil {
+					if days < 0 {
+						return nil, fmt.Errorf("invalid check-dates-in-future value '%s', expected a number of days", args[i+1])
+					}
+					config.FutureDays = days
+					i++
+				}
+			}
+			i++
+		} else if arg == "--check-dates-in-past" {
+			config.CheckPast = true
+			i++
+		} else if arg == "--validate" {
+			config.Validate = true
+			i++
+		} else if arg == "--require-tags" {
+			config.RequireTags = true
+			i++
+		} else if arg == "--exclude-incomplete" {
+			config.ExcludeIncomplete = true
+			i++
+		} else if arg == "--use-keywords-as-tags" {
+			config.KeywordsAsTags = true
+			i++
+		} else if arg == "--tag" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("tag flag requires a value")
+			}
+			config.FilterTags = append(config.FilterTags, args[i+1])
+			i += 2
+		} else if arg == "--list-tags" {
+			config.ListTags = true
+			i++
+		} else if arg == "--output-encoding" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("output-encoding flag requires a value")
+			}
+			if _, err := outputTransformer(args[i+1]); err != nil {
+				return nil, err
+			}
+			config.OutputEncoding = args[i+1]
+			i += 2
+		} else if arg == "--wrap" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("wrap flag requires a value")
+			}
+			wrap, err := strconv.Atoi(args[i+1])
+			if err != nil || wrap < 1 {
+				return nil, fmt.Errorf("invalid wrap value '%s', expected a positive number of columns", args[i+1])
+			}
+			config.Wrap = wrap
+			i += 2
+		} else if arg == "--public" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("public flag requires a path")
+			}
+			config.PublicPath = args[i+1]
+			i += 2
+		} else if arg == "--ignore-section" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("ignore-section flag requires a section name")
+			}
+			config.IgnoreSections = append(config.IgnoreSections, args[i+1])
+			i += 2
+		} else if arg == "-y" || arg == "--year" {
+			given["--year"] = true
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("year flag requires a value")
+			}
+			year, err := resolveYear(args[i+1], time.Now())
+			if err != nil {
+				return nil, err
+			}
+			config.From = year
+			config.To = year.AddDate(0, 11, 0)
+			i += 2
+		} else if arg == "--week" {
+			given["--week"] = true
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("week flag requires a value")
+			}
+			config.Week = args[i+1]
+			i += 2
+		} else if arg == "--fiscal-year" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("fiscal-year flag requires a month")
+			}
+			month, err := strconv.Atoi(args[i+1])
+			if err != nil || month < 1 || month > 12 {
+				return nil, fmt.Errorf("invalid fiscal year start month '%s', expected 1-12", args[i+1])
+			}
+			config.FiscalYear = month
+			i += 2
+		} else if arg == "--quarter" {
+			given["--quarter"] = true
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("quarter flag requires a value")
+			}
+			quarter, err := strconv.Atoi(args[i+1])
+			if err != nil || quarter < 1 || quarter > 4 {
+				return nil, fmt.Errorf("invalid quarter '%s', expected 1-4", args[i+1])
+			}
+			config.Quarter = quarter
+			i += 2
+		} else if arg == "--from" || arg == "--to" {
+			given[arg] = true
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("%s flag requires a value", strings.TrimLeft(arg, "-"))
+			}
+			month, err := time.Parse("2006-01", args[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s month format '%s', expected YYYY-MM", strings.TrimLeft(arg, "-"), args[i+1])
+			}
+			if arg == "--from" {
+				config.From = month
+			} else {
+				config.To = month
+			}
+			i += 2
+		} else if arg == "--show-empty-months" {
+			given[arg] = true
+			config.ShowEmptyMonths = true
+			i++
+		} else if arg == "--recent" {
+			given["--recent"] = true
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("recent flag requires a number of months")
+			}
+			recent, err := strconv.Atoi(args[i+1])
+			if err != nil || recent < 1 {
+				return nil, fmt.Errorf("invalid number of months '%s'", args[i+1])
+			}
+			config.Recent = recent
+			i += 2
+		} else if arg == "--group-by-week" {
+			config.GroupByWeek = true
+			i++
+		} else if arg == "--group-by-month" {
+			config.GroupByMonth = true
+			i++
+		} else if arg == "--word-count-goal" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("word-count-goal flag requires a value")
+			}
+			goal, err := strconv.Atoi(args[i+1])
+			if err != nil || goal < 1 {
+				return nil, fmt.Errorf("invalid word count goal '%s'", args[i+1])
+			}
+			config.WordCountGoal = goal
+			i += 2
+		} else if arg == "--unique-tags-per-month" {
+			config.UniqueTags = true
+			i++
+		} else if arg == "--new-tags-per-month" {
+			config.NewTags = true
+			i++
+		} else if arg == "--abandoned-tags" {
+			config.AbandonedTags = true
+			i++
+		} else if arg == "--min-tag-uses" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("min-tag-uses flag requires a value")
+			}
+			uses, err := strconv.Atoi(args[i+1])
+			if err != nil || uses < 1 {
+				return nil, fmt.Errorf("invalid number of tag uses '%s'", args[i+1])
+			}
+			config.MinTagUses = uses
+			i += 2
+		} else if arg == "--post-length-histogram" {
+			config.LengthHistogram = true
+			i++
+		} else if arg == "--content-hash" {
+			config.ContentHash = true
+			i++
+		} else if arg == "--correlation" {
+			config.Correlation = true
+			i++
+		} else if arg == "--first-post-of-day" {
+			config.FirstPostOfDay = true
+			i++
+		} else if arg == "--only-with-posts" {
+			given[arg] = true
+			config.OnlyWithPosts = true
+			i++
+		} else if arg == "--show-holidays" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("show-holidays flag requires a country code")
+			}
+			config.Holidays = newHolidayCalendar(args[i+1])
+			if config.Holidays == nil {
+				return nil, fmt.Errorf("unsupported holiday country '%s', expected one of %s",
+					args[i+1], strings.Join(supportedCountries(), ", "))
+			}
+			i += 2
+		} else if arg == "--exclude-holidays" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("exclude-holidays flag requires a country code")
+			}
+			config.RestDays.Holidays = newHolidayCalendar(args[i+1])
+			if config.RestDays.Holidays == nil {
+				return nil, fmt.Errorf("unsupported holiday country '%s', expected one of %s",
+					args[i+1], strings.Join(supportedCountries(), ", "))
+			}
+			i += 2
+		} else if arg == "--custom-holidays" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("custom-holidays flag requires a file path or URL")
+			}
+			config.CustomHolidays = args[i+1]
+			i += 2
+		} else if arg == "--highlight-dates" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("highlight-dates flag requires a file path")
+			}
+			config.HighlightDates = args[i+1]
+			i += 2
+		} else if arg == "--exclude-dates" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("exclude-dates flag requires a file path")
+			}
+			config.ExcludeDates = args[i+1]
+			i += 2
+		} else if arg == "--personal-rest-days" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("personal-rest-days flag requires a list of days")
+			}
+			weekdays, err := parseRestWeekdays(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			config.RestDays.Weekdays = weekdays
+			i += 2
+		} else if arg == "--target-cadence" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("target-cadence flag requires a value")
+			}
+			cadence, err := parseCadence(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			config.Cadence = &cadence
+			i += 2
+		} else if arg == "--best-time-to-publish" {
+			config.BestTime = true
+			i++
+		} else if arg == "--moving-average" {
+			// Window is optional, default to a week
+			config.MovingAverage = 7
+			i++
+			if i < len(args) {
+				if days, err := strconv.Atoi(args[i]); err == nil {
+					if days < 1 {
+						return nil, fmt.Errorf("moving-average window must be at least 1 day")
+					}
+					config.MovingAverage = days
+					i++
+				}
+			}
+		} else if arg == "--percentile-days" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("percentile-days flag requires a value")
+			}
+			percentile, err := strconv.Atoi(args[i+1])
+			if err != nil || (percentile != 50 && percentile != 75 && percentile != 90 && percentile != 95 && percentile != 99) {
+				return nil, fmt.Errorf("percentile-days must be one of 50, 75, 90, 95, or 99")
+			}
+			config.PercentileDays = percentile
+			i += 2
+		} else if arg == "--count-posts-per-author" {
+			config.CountPerAuthor = true
+			i++
+		} else if arg == "--top-authors" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("top-authors flag requires a value")
+			}
+			top, err := strconv.Atoi(args[i+1])
+			if err != nil || top < 1 {
+				return nil, fmt.Errorf("invalid number of authors '%s'", args[i+1])
+			}
+			config.TopAuthors = top
+			i += 2
+		} else if arg == "--author-breakdown" {
+			config.AuthorBreakdown = true
+			i++
+		} else if arg == "--author-limit" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("author-limit flag requires a value")
+			}
+			limit, err := strconv.Atoi(args[i+1])
+			if err != nil || limit < 1 {
+				return nil, fmt.Errorf("invalid number of authors '%s'", args[i+1])
+			}
+			config.AuthorLimit = limit
+			i += 2
+		} else if arg == "--tag-breakdown" {
+			config.TagBreakdown = true
+			i++
+		} else if arg == "--tag-limit" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("tag-limit flag requires a value")
+			}
+			limit, err := strconv.Atoi(args[i+1])
+			if err != nil || limit < 1 {
+				return nil, fmt.Errorf("invalid number of tags '%s'", args[i+1])
+			}
+			config.TagLimit = limit
+			i += 2
+		} else if arg == "--ignore-bundles" {
+			config.IgnoreBundles = true
+			i++
+		} else if arg == "--apply-cascade" {
+			config.ApplyCascade = true
+			i++
+		} else if arg == "--count-by-section" {
+			config.CountBySection = true
+			i++
+		} else if arg == "--show-paths" {
+			config.ShowPaths = true
+			i++
+		} else if arg == "--ascii" {
+			config.ASCII = true
+			i++
+		} else if arg == "--config-dump" {
+			config.ConfigDump = true
+			i++
+		} else if arg == "--migrate-config" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("migrate-config flag requires a file path")
+			}
+			config.MigrateConfig = args[i+1]
+			i += 2
+		} else if arg == "--self-update" {
+			config.SelfUpdate = true
+			i++
+		} else if arg == "--check-update" {
+			config.CheckUpdate = true
+			i++
+		} else if arg == "--metrics" {
+			config.Metrics = true
+			i++
+		} else if arg == "--metrics-file" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("metrics-file flag requires a file path")
+			}
+			config.Metrics = true
+			config.MetricsFile = args[i+1]
+			i += 2
+		} else if arg == "--elapsed" {
+			config.Elapsed = true
+			i++
+		} else if arg == "--filter-regex" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("filter-regex flag requires a pattern")
+			}
+			// Compiled once here rather than for every post
+			re, err := regexp.Compile(args[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter-regex '%s': %v", args[i+1], err)
+			}
+			config.FilterRegexes = append(config.FilterRegexes, re)
+			i += 2
+		} else if arg == "--no-clone" {
+			config.NoClone = true
+			i++
+		} else if arg == "--gitlab" {
+			config.GitLab = true
+			i++
+		} else if arg == "--combine" {
+			config.Combine = true
+			i++
+		} else if arg == "--null-separated" {
+			config.NullSeparated = true
+			i++
+		} else if arg == "--print-config-path" {
+			config.PrintConfigPath = true
+			i++
+		} else if arg == "-q" || arg == "--quiet" {
+			config.Quiet = true
+			i++
+		} else if arg == "-s" || arg == "--stats" {
+			config.ShowStats = true
+			i++
+		} else if arg == "-o" || arg == "--output" {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("output flag requires a format")
+			}
+			config.Output = args[i+1]
+			i += 2
+		} else if strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		} else {
+			// This should be the project path
+			paths = append(paths, arg)
+			if config.ProjectPath == "" {
+				config.ProjectPath = arg
+			} else {
+				// Only valid with --combine, which may come later
+				config.CombinePaths = append(config.CombinePaths, arg)
+			}
+			i++
+		}
+	}
+
+	if config.NullSeparated && len(paths) > 0 {
+		return nil, fmt.Errorf("--null-separated reads project paths from stdin, not the command line: %s", paths[0])
+	}
+	if len(config.CombinePaths) > 0 && !config.Combine {
+		return nil, fmt.Errorf("unexpected argument: %s", config.CombinePaths[0])
+	}
+	if config.Combine && config.PublicPath != "" {
+		return nil, fmt.Errorf("--combine can't be used with --public")
+	}
+
+	// OWNER/REPO is shorthand for a GitHub (or GitLab) repository
+	if !config.NoClone {
+		config.ProjectPath = expandRepoShorthand(config.ProjectPath, config.GitLab)
+		for i, path := range config.CombinePaths {
+			config.CombinePaths[i] = expandRepoShorthand(path, config.GitLab)
+		}
+	}
+
+	for _, pair := range conflictingFlags {
+		if given[pair[0]] && given[pair[1]] {
+			return nil, fmt.Errorf("%s and %s can't be used together", pair[0], pair[1])
+		}
+	}
+
+	// A fiscal year shifts --year to start in the given month
+	if config.FiscalYear > 1 && given["--year"] {
+		config.From = config.From.AddDate(0, config.FiscalYear-1, 0)
+		config.To = config.From.AddDate(0, 11, 0)
+	}
+
+	// A quarter narrows --year, or the current year, to three months
+	if config.Quarter > 0 {
+		year := time.Date(time.Now().Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		if given["--year"] {
+			year = config.From
+		}
+		config.From = year.AddDate(0, (config.Quarter-1)*3, 0)
+		config.To = config.From.AddDate(0, 2, 0)
+		config.ShowEmptyMonths = !config.OnlyWithPosts
+	}
+
+	// A year of a young blog is mostly empty months, so leave them out
+	// unless they were asked for
+	if given["--year"] && !config.ShowEmptyMonths {
+		config.OnlyWithPosts = true
+	}
+	if config.ShowEmptyMonths && config.OnlyWithPosts {
+		return nil, fmt.Errorf("--show-empty-months and --only-with-posts can't be used together")
+	}
+
+	// A generated site can be read without the project source, and
+	// updating needs no project at all
+	if config.ProjectPath == "" && config.PublicPath == "" && !config.SelfUpdate && !config.CheckUpdate && !config.NullSeparated {
+		return nil, fmt.Errorf("missing project path")
+	}
+
+	if _, err := outputTransformer(config.OutputEncoding); err != nil {
+		return nil, err
+	}
+
+	if config.Output != "calendar" && config.Output != "json" && config.Output != "csv" {
+		return nil, fmt.Errorf("invalid output format '%s', expected calendar, json, or csv", config.Output)
+	}
+	if config.Output == "csv" && !config.GroupByWeek && !config.GroupByMonth {
+		return nil, fmt.Errorf("csv output needs --group-by-week or --group-by-month")
+	}
+	if config.GroupByWeek && config.GroupByMonth {
+		return nil, fmt.Errorf("--group-by-week and --group-by-month can't be used together")
+	}
+
+	if !config.From.IsZero() && !config.To.IsZero() && config.From.After(config.To) {
+		return nil, fmt.Errorf("range starts after it ends: %s to %s", config.From.Format("2006-01"), config.To.Format("2006-01"))
+	}
+
+	// Validate month format if provided
+	if config.Month != nil {
+		month, err := resolveMonth(*config.Month, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		config.Month = &month
+	}
+
+	if config.Week != "" {
+		weekStart, err := parseISOWeek(config.Week)
+		if err != nil {
+			return nil, err
+		}
+		config.WeekStart = weekStart
+	}
+
+	config.Given = given
+	return config, nil
+}
+
+// parseISOWeek returns the Monday that starts an ISO week given as
+// YYYY-Www, such as 2024-W30.
+func parseISOWeek(value string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(value, "%4d-W%2d", &year, &week); err != nil || len(value) != len("2006-W01") {
+		return time.Time{}, fmt.Errorf("invalid week format '%s', expected YYYY-Www", value)
+	}
+
+	// January 4th is always in week 1, so step back to that week's Monday
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if gotYear, gotWeek := monday.ISOWeek(); gotYear != year || gotWeek != week {
+		return time.Time{}, fmt.Errorf("%d has no ISO week %d", year, week)
+	}
+	return monday, nil
+}
+
+// resolveMonth turns a --month value into YYYY-MM form. A bare year means
+// the current calendar month within that year; current, next, prev (or
+// last) and signed offsets like +2 or -3 are relative to the current month.
+func resolveMonth(value string, now time.Time) (string, error) {
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	switch value {
+	case "current":
+		return thisMonth.Format("2006-01"), nil
+	case "next":
+		return thisMonth.AddDate(0, 1, 0).Format("2006-01"), nil
+	case "prev", "last":
+		return thisMonth.AddDate(0, -1, 0).Format("2006-01"), nil
+	}
+	if isMonthOffset(value) {
+		offset, _ := strconv.Atoi(value)
+		return thisMonth.AddDate(0, offset, 0).Format("2006-01"), nil
+	}
+	if year, err := time.Parse("2006", value); err == nil {
+		return time.Date(year.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format("2006-01"), nil
+	}
+	if _, err := time.Parse("2006-01", value); err != nil {
+		return "", fmt.Errorf("invalid month format '%s', expected YYYY-MM, YYYY, current, next, prev or an offset like -3", value)
+	}
+	return value, nil
+}
+
+// resolveYear turns a --year value into January of that year. Besides
+// YYYY it accepts last, current and next, relative to the current year.
+func resolveYear(value string, now time.Time) (time.Time, error) {
+	thisYear := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	switch value {
+	case "last":
+		return thisYear.AddDate(-1, 0, 0), nil
+	case "current":
+		return thisYear, nil
+	case "next":
+		return thisYear.AddDate(1, 0, 0), nil
+	}
+	year, err := time.Parse("2006", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid year format '%s', expected YYYY, last, current or next", value)
+	}
+	return year, nil
+}
+
+// isMonthOffset reports whether value is a signed month offset such as +2
+// or -3.
+func isMonthOffset(value string) bool {
+	if len(value) < 2 || (value[0] != '+' && value[0] != '-') {
+		return false
+	}
+	for _, r := range value[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// terminalWidthProvider reports the number of columns available for
+// output, so layouts can be computed for a width other than the real
+// terminal's.
+type terminalWidthProvider interface {
+	Width() int
+}
+
+// terminalWidth provides the width of the terminal running the program.
+type terminalWidth struct{}
+
+func (terminalWidth) Width() int {
+	return getTerminalWidth()
+}
+
+// getTerminalWidth tries, in order, the terminal itself, the COLUMNS
+// variable, and stty before settling on 80 columns.
+func getTerminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdin.Fd()))
+	if err == nil && width > 0 {
+		return width
+	}
+	logVerbose("Terminal width not available from terminal (%v), trying COLUMNS", err)
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		logVerbose("Using terminal width %d from COLUMNS", columns)
+		return columns
+	}
+	logVerbose("COLUMNS not set, trying stty size")
+
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	if output, err := cmd.Output(); err == nil {
+		// stty prints "rows columns"
+		fields := strings.Fields(string(output))
+		if len(fields) == 2 {
+			if columns, err := strconv.Atoi(fields[1]); err == nil && columns > 0 {
+				logVerbose("Using terminal width %d from stty", columns)
+				return columns
+			}
+		}
+	}
+
+	logVerbose("Terminal width not detected, using 80 columns")
+	return 80
+}
+
+func getTerminalHeight() int {
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height <= 0 {
+		return 24
+	}
+	return height
+}
+
+// printElapsed writes the time taken by each phase of a run to w.
+// Walk time excludes the parsing and filtering done during the walk.
+func printElapsed(w io.Writer, walkStats WalkStats, kept int, render, total time.Duration) {
+	walk := walkStats.Walk - walkStats.Parse - walkStats.Filter
+	fmt.Fprintf(w, "Walk:   %.2fs (%d files)\n", walk.Seconds(), walkStats.Files)
+	fmt.Fprintf(w, "Parse:  %.2fs (%d posts)\n", walkStats.Parse.Seconds(), walkStats.Posts)
+	fmt.Fprintf(w, "Filter: %.2fs (%d posts after filter)\n", walkStats.Filter.Seconds(), kept)
+	fmt.Fprintf(w, "Render: %.2fs\n", render.Seconds())
+	fmt.Fprintf(w, "Total:  %.2fs\n", total.Seconds())
+}
+
+// logVerbose prints a diagnostic line to stderr when --verbose is set.
+func logVerbose(format string, args ...interface{}) {
+	if verboseOutput != nil {
+		fmt.Fprintf(verboseOutput, format+"\n", args...)
+	}
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// waitForPager pauses output until the user presses Enter.
+func waitForPager(w io.Writer) {
+	fmt.Fprint(w, "-- More -- (press Enter)")
+	bufio.NewReader(os.Stdin).ReadString('\n')
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runEach runs the program once for every NUL-separated project path read
+// from paths, with the remaining arguments, and returns the highest exit
+// code. Each project's output is headed by its path.
+func runEach(args []string, paths io.Reader, stdout, stderr io.Writer) int {
+	var rest []string
+	for _, arg := range args {
+		if arg != "--null-separated" {
+			rest = append(rest, arg)
+		}
+	}
+
+	input, err := io.ReadAll(paths)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error reading project paths: %v\n", err)
+		return 1
+	}
+
+	code := 0
+	first := true
+	for _, path := range strings.Split(string(input), "\x00") {
+		// Tolerate a trailing newline from tools that don't end with NUL
+		path = strings.TrimRight(path, "\n")
+		if path == "" {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(stdout)
+		}
+		first = false
+		fmt.Fprintf(stdout, "==> %s <==\n", path)
+		if c := run(append(rest[:len(rest):len(rest)], path), stdout, stderr); c > code {
+			code = c
+		}
+	}
+	return code
+}
+
+// run is the whole program: it parses args, writes its output to stdout
+// and stderr, and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	start := time.Now()
+	config, err := parseArgs(args)
+	if err != nil {
+		fmt.Fprintf(stdout, "Error: %v\n\n", err)
+		fmt.Fprintln(stdout, "Usage: hugo-calendar <path-to-hugo-project-or-repository-url> [options]")
+		fmt.Fprintln(stdout, "Options:")
+		fmt.Fprintln(stdout, "  -f, --filter TEXT               Exclude posts containing TEXT in their body")
+		fmt.Fprintln(stdout, "      --filter-regex PATTERN      Exclude posts whose body matches PATTERN (repeatable)")
+		fmt.Fprintln(stdout, "  -c, --counts                    Show post counts instead of day numbers")
+		fmt.Fprintln(stdout, "  -m, --month YYYY-MM             Show only the specified month (default: current month)")
+		fmt.Fprintln(stdout, "                                  Also accepts current, next, prev, last or an offset like +2 or -3")
+		fmt.Fprintln(stdout, "      --export-influx FILE        Write daily post counts to FILE in InfluxDB line protocol")
+		fmt.Fprintln(stdout, "      --export-statsd ADDR        Send daily post counts as StatsD gauges (default: localhost:8125)")
+		fmt.Fprintln(stdout, "      --notify                    Send a desktop notification on streak milestones")
+		fmt.Fprintln(stdout, "      --notify-every N            Streak milestone interval in days for --notify (default: 7)")
+		fmt.Fprintln(stdout, "      --webhook URL               POST post statistics as JSON to URL after rendering")
+		fmt.Fprintln(stdout, "      --webhook-secret KEY        Sign webhook payloads with an X-Hub-Signature-256 header")
+		fmt.Fprintln(stdout, "      --strict                    Exit with an error when a warning occurs")
+		fmt.Fprintln(stdout, "      --slack-webhook URL         Post a summary of the month's activity to a Slack webhook")
+		fmt.Fprintln(stdout, "      --goal N                    Monthly post goal used in summaries")
+		fmt.Fprintln(stdout, "      --github-actions            Write a step summary and annotations for GitHub Actions")
+		fmt.Fprintln(stdout, "      --no-pager                  Don't pause when output is taller than the terminal")
+		fmt.Fprintln(stdout, "  -v, --verbose                   Print diagnostic details to stderr")
+		fmt.Fprintln(stdout, "      --count-images              Count images per post; with --counts, show image counts")
+		fmt.Fprintln(stdout, "  -o, --output FORMAT             Output format: calendar (default), json, or csv")
+		fmt.Fprintln(stdout, "      --count-links               Count external links per post")
+		fmt.Fprintln(stdout, "      --count-code-blocks         Count fenced code blocks per post")
+		fmt.Fprintln(stdout, "      --count-headings            Count Markdown headings by level per post")
+		fmt.Fprintln(stdout, "      --readability               Compute a Flesch reading ease score per post")
+		fmt.Fprintln(stdout, "  -t, --title-list                List the date and title of each post after the calendar")
+		fmt.Fprintln(stdout, "      --extract-field NAME        Include a custom front matter field in listings (repeatable)")
+		fmt.Fprintln(stdout, "      --filter-field F=V          Only count posts whose front matter field F is V (repeatable)")
+		fmt.Fprintln(stdout, "      --filter-has-aliases        Only count posts with aliases (redirect targets)")
+		fmt.Fprintln(stdout, "      --series NAME               Only count posts in the named series")
+		fmt.Fprintln(stdout, "      --list-series               List every series with its post count")
+		fmt.Fprintln(stdout, "      --featured-only             Only count posts marked featured: true")
+		fmt.Fprintln(stdout, "      --type TYPE                 Only count posts with this content type (repeatable)")
+		fmt.Fprintln(stdout, "      --layout LAYOUT             Only count posts with this layout (repeatable)")
+		fmt.Fprintln(stdout, "      --day-of-month N            Only count posts published on day N (1-31) of a month")
+		fmt.Fprintln(stdout, "      --no-title                  Show [redacted] in place of post titles")
+		fmt.Fprintln(stdout, "      --anonymize                 Show short hashes in place of file paths")
+		fmt.Fprintln(stdout, "      --post-url-base URL         Link titles in the title list to their pages under URL")
+		fmt.Fprintln(stdout, "      --no-color                  Disable colors and terminal hyperlinks")
+		fmt.Fprintln(stdout, "      --deduplicate-titles        Warn about posts that share a title (exit 3 with --strict)")
+		fmt.Fprintln(stdout, "      --check-dates-in-future N   Warn about posts dated more than N days ahead (default: 365)")
+		fmt.Fprintln(stdout, "      --check-dates-in-past       Warn about posts dated before the project's first git commit")
+		fmt.Fprintln(stdout, "      --validate                  Check front matter of every post and exit 2 if any fail")
+		fmt.Fprintln(stdout, "      --require-tags              Treat posts without tags as incomplete (shown dimmed)")
+		fmt.Fprintln(stdout, "      --exclude-incomplete        Don't count incomplete posts at all")
+		fmt.Fprintln(stdout, "      --tag TAG                   Only count posts with this tag (repeatable)")
+		fmt.Fprintln(stdout, "      --list-tags                 List every tag with its post count")
+		fmt.Fprintln(stdout, "      --abandoned-tags            List tags used by fewer than --min-tag-uses posts")
+		fmt.Fprintln(stdout, "      --min-tag-uses N            Posts a tag needs to not count as abandoned (default: 2)")
+		fmt.Fprintln(stdout, "      --use-keywords-as-tags      Use keywords as the tags of posts that have no tags")
+		fmt.Fprintln(stdout, "      --output-encoding ENC       Encode output as UTF-8 (default), ASCII, or LATIN-1")
+		fmt.Fprintln(stdout, "      --wrap N                    Wrap titles in the title list at N columns (default: terminal width)")
+		fmt.Fprintln(stdout, "      --public PATH               Read post dates from a generated public/ directory")
+		fmt.Fprintln(stdout, "      --ignore-section SECTION    Skip a top-level directory of the posts (repeatable)")
+		fmt.Fprintln(stdout, "  -y, --year YYYY                 Show only months in the given year")
+		fmt.Fprintln(stdout, "                                  Also accepts last, current or next")
+		fmt.Fprintln(stdout, "      --from YYYY-MM              Show only months from YYYY-MM onwards")
+		fmt.Fprintln(stdout, "      --to YYYY-MM                Show only months up to YYYY-MM")
+		fmt.Fprintln(stdout, "      --week YYYY-Www             Show only the given ISO week, such as 2024-W30")
+		fmt.Fprintln(stdout, "      --quarter N                 Show quarter N (1-4) of --year or the current year")
+		fmt.Fprintln(stdout, "      --fiscal-year MONTH         Start --year in MONTH (1-12), e.g. 4 for April to March")
+		fmt.Fprintln(stdout, "      --show-empty-months         Show every month in the --year or --from/--to range")
+		fmt.Fprintln(stdout, "      --group-by-week             List posts per ISO week instead of drawing the calendar")
+		fmt.Fprintln(stdout, "      --group-by-month            List posts per month instead of drawing the calendar")
+		fmt.Fprintln(stdout, "      --post-length-histogram     Chart how many posts fall in each range of word counts")
+		fmt.Fprintln(stdout, "      --content-hash              Report posts whose body changed since the last run")
+		fmt.Fprintln(stdout, "      --correlation               Compare average posts on days with and without each tag")
+		fmt.Fprintln(stdout, "      --first-post-of-day         Count only the first post of each day")
+		fmt.Fprintln(stdout, "      --recent N                  Show only the N most recent months with posts")
+		fmt.Fprintln(stdout, "      --only-with-posts           Skip months without posts (default with --year)")
+		fmt.Fprintln(stdout, "      --show-holidays COUNTRY     Underline public holidays (US, GB, DE, FR, CA)")
+		fmt.Fprintln(stdout, "      --exclude-holidays COUNTRY  Don't let public holidays break streaks")
+		fmt.Fprintln(stdout, "      --custom-holidays FILE      Add holidays from a YAML file or URL to both of the above")
+		fmt.Fprintln(stdout, "      --highlight-dates FILE      Highlight the YYYY-MM-DD dates listed in FILE, one per line")
+		fmt.Fprintln(stdout, "      --exclude-dates FILE        Don't count posts on the YYYY-MM-DD dates listed in FILE")
+		fmt.Fprintln(stdout, "      --personal-rest-days DAYS   Days of the week that don't break streaks, e.g. SAT,SUN")
+		fmt.Fprintln(stdout, "      --target-cadence N/PERIOD   Compare --stats months to a target, e.g. 3/week")
+		fmt.Fprintln(stdout, "      --word-count-goal N         Compare --stats months to a goal of N words a month")
+		fmt.Fprintln(stdout, "      --unique-tags-per-month     Add the number of distinct tags used each month to --stats")
+		fmt.Fprintln(stdout, "      --new-tags-per-month        Add the number of tags used for the first time each month to --stats")
+		fmt.Fprintln(stdout, "      --best-time-to-publish      Print the weekday and day of month you post on most")
+		fmt.Fprintln(stdout, "      --moving-average [N]        Add daily counts with an N-day average to --stats (default: 7)")
+		fmt.Fprintln(stdout, "      --percentile-days P         Highlight days with more posts than the Pth percentile")
+		fmt.Fprintln(stdout, "      --count-posts-per-author    Add posts per month by author to --stats")
+		fmt.Fprintln(stdout, "      --top-authors N             Authors shown by --count-posts-per-author (default: 5)")
+		fmt.Fprintln(stdout, "      --author-breakdown          Draw a separate calendar for each author")
+		fmt.Fprintln(stdout, "      --author-limit N            Draw only the N authors with the most posts")
+		fmt.Fprintln(stdout, "      --tag-breakdown             Draw a separate calendar for each tag, most used first")
+		fmt.Fprintln(stdout, "      --tag-limit N               Draw only the N most used tags (default: 10)")
+		fmt.Fprintln(stdout, "      --ignore-bundles            Skip posts whose directory has images or other resources")
+		fmt.Fprintln(stdout, "      --apply-cascade             Apply cascade front matter from section _index.md files")
+		fmt.Fprintln(stdout, "      --count-by-section          Count posts from every content section and show a breakdown")
+		fmt.Fprintln(stdout, "      --show-paths                Show posts as a tree of the content directory")
+		fmt.Fprintln(stdout, "      --ascii                     Draw --show-paths trees and calendar marks with ASCII characters")
+		fmt.Fprintln(stdout, "      --config-dump               Print the resolved configuration as YAML and exit")
+		fmt.Fprintln(stdout, "      --migrate-config FILE       Save the current options as a config file and exit")
+		fmt.Fprintln(stdout, "      --self-update               Replace this binary with the latest release")
+		fmt.Fprintln(stdout, "      --check-update              Report whether a newer release is available")
+		fmt.Fprintln(stdout, "      --metrics                   Write OpenMetrics text to stderr after rendering")
+		fmt.Fprintln(stdout, "      --metrics-file FILE         Write the --metrics output to FILE instead")
+		fmt.Fprintln(stdout, "      --elapsed                   Print the time taken by each phase to stderr")
+		fmt.Fprintln(stdout, "      --print-config-path         Print the config files that were read to stderr")
+		fmt.Fprintln(stdout, "      --no-clone                  Don't clone project paths that are http(s):// or git+ URLs")
+		fmt.Fprintln(stdout, "      --gitlab                    Read OWNER/REPO project paths as GitLab rather than GitHub repos")
+		fmt.Fprintln(stdout, "      --combine                   Sum the posts of every project path given into one calendar")
+		fmt.Fprintln(stdout, "      --null-separated            Read NUL-separated project paths from stdin and show each")
+		fmt.Fprintln(stdout, "  -q, --quiet                     Only report --validate results through the exit code")
+		fmt.Fprintln(stdout, "  -s, --stats                     Print posting statistics after the calendar")
+		fmt.Fprintln(stdout)
+		fmt.Fprintln(stdout, "Options are read from ~/.config/hugo-calendar/config.yaml, then from")
+		fmt.Fprintln(stdout, ".hugo-calendar.yaml in the project root, then from the command line.")
+		fmt.Fprintln(stdout, "Each level overrides the one before; see --config-dump for the keys.")
+		return 1
+	}
+	if config.NullSeparated {
+		return runEach(args, os.Stdin, stdout, stderr)
+	}
+	verboseOutput = nil
+	if config.Verbose {
+		verboseOutput = stderr
+	}
+	if config.NoColor {
+		color.NoColor = true
+	}
+	colors := newColorScheme()
+
+	if config.PrintConfigPath {
+		if len(config.ConfigFiles) == 0 {
+			fmt.Fprintln(stderr, "(none)")
+		}
+		for _, path := range config.ConfigFiles {
+			fmt.Fprintln(stderr, path)
+		}
+	}
+
+	// Everything printed from here on goes through the output encoding, so
+	// exit through exit() to flush it first
+	flushOutput := func() {}
+	if t, _ := outputTransformer(config.OutputEncoding); t != nil {
+		stdout, flushOutput = setOutputEncoding(stdout, t)
+	}
+	exit := func(code int) int {
+		flushOutput()
+		return code
+	}
+
+	// Custom holidays are shown on the calendar and skipped in streaks
+	// alongside any built-in country holidays
+	if config.CustomHolidays != "" {
+		custom, err := loadCustomHolidays(config.CustomHolidays)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error loading custom holidays: %v\n", err)
+			return exit(1)
+		}
+		config.Holidays = config.Holidays.withCustom(custom)
+		config.RestDays.Holidays = config.RestDays.Holidays.withCustom(custom)
+	}
+
+	if config.HighlightDates != "" {
+		config.HighlightDays, err = readDateList(config.HighlightDates)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error loading highlight dates: %v\n", err)
+			return exit(1)
+		}
+	}
+
+	if config.ExcludeDates != "" {
+		config.ExcludeDays, err = readDateList(config.ExcludeDates)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error loading excluded dates: %v\n", err)
+			return exit(1)
+		}
+	}
+
+	if config.SelfUpdate {
+		if err := selfUpdate(stdout); err != nil {
+			fmt.Fprintf(stdout, "Error updating: %v\n", err)
+			return exit(1)
+		}
+		return exit(0)
+	}
+
+	if config.CheckUpdate {
+		checkUpdate(stdout)
+		return exit(0)
+	}
+
+	if config.ConfigDump {
+		if err := dumpConfig(stdout, config); err != nil {
+			fmt.Fprintf(stdout, "Error writing configuration: %v\n", err)
+			return exit(1)
+		}
+		return exit(0)
+	}
+
+	if config.MigrateConfig != "" {
+		if err := migrateConfig(stdout, config.MigrateConfig, config); err != nil {
+			fmt.Fprintf(stdout, "Error writing configuration: %v\n", err)
+			return exit(1)
+		}
+		return exit(0)
+	}
+
+	// Caches and exports know a project by the path it was given as, which
+	// for a cloned repository is its URL rather than the temporary clone
+	projectID := config.ProjectPath
+
+	// Repository URLs are cloned to a temporary directory for the scan. The
+	// past date check needs the first commit, so only then is the whole
+	// history fetched
+	if !config.NoClone && config.PublicPath == "" {
+		paths := append([]string{config.ProjectPath}, config.CombinePaths...)
+		for i, path := range paths {
+			if !isRemoteProject(path) {
+				continue
+			}
+			dir, err := cloneProject(path, config.CheckPast, stderr)
+			if err != nil {
+				fmt.Fprintf(stdout, "Error cloning project: %v\n", err)
+				return exit(1)
+			}
+			defer os.RemoveAll(dir)
+			paths[i] = dir
+		}
+		config.ProjectPath, config.CombinePaths = paths[0], paths[1:]
+	}
+
+	var postCounts map[string]int
+	var postMetas map[string][]PostMeta
+	var contentRoot string // The directory posts were read from
+	var walkStats WalkStats
+
+	if config.PublicPath != "" {
+		contentRoot = config.PublicPath
+		if _, err := os.Stat(config.PublicPath); os.IsNotExist(err) {
+			fmt.Fprintf(stdout, "Public directory not found: %s\n", config.displayPath(config.PublicPath))
+			return exit(1)
+		}
+
+		postCounts, postMetas, walkStats, err = parsePublicDir(config.PublicPath, config)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error parsing public directory: %v\n", err)
+			return exit(1)
+		}
+	} else {
+		// With --combine every project's posts are summed into one set
+		projectPaths := append([]string{config.ProjectPath}, config.CombinePaths...)
+		for _, projectPath := range projectPaths {
+			contentPath := filepath.Join(projectPath, "content")
+			postsPath := filepath.Join(contentPath, "posts")
+			if config.CountBySection {
+				// Sections are the top-level directories of content/, so
+				// read all of them rather than just posts
+				postsPath = contentPath
+			}
+
+			// Check if posts directory exists
+			if _, err := os.Stat(postsPath); os.IsNotExist(err) {
+				fmt.Fprintf(stdout, "Posts directory not found: %s\n", config.displayPath(postsPath))
+				return exit(1)
+			}
+
+			// Parse all posts and count by date
+			counts, metas, stats, err := parsePostsAndCount(stdout, contentPath, postsPath, config)
+			if err != nil {
+				fmt.Fprintf(stdout, "Error parsing posts: %v\n", err)
+				return exit(1)
+			}
+			if postCounts == nil {
+				contentRoot = postsPath
+				postCounts, postMetas, walkStats = counts, metas, stats
+				continue
+			}
+			for dateKey, count := range counts {
+				postCounts[dateKey] += count
+			}
+			for dateKey, dayMetas := range metas {
+				postMetas[dateKey] = append(postMetas[dateKey], dayMetas...)
+			}
+			walkStats.add(stats)
+		}
+	}
+
+	if config.Validate {
+		return exit(validatePosts(stdout, postMetas, config))
+	}
+
+	if config.FirstPostOfDay {
+		keepFirstPostOfDay(postCounts, postMetas)
+	}
+
+	if len(postCounts) == 0 {
+		fmt.Fprintln(stdout, "No posts found in the Hugo project.")
+		flushOutput()
+		return 0
+	}
+
+	calendarOptions := config.calendarOptions()
+	calendarOptions.Colors = colors
+	// Only paginate when a person is reading the output and can press Enter
+	calendarOptions.Paginate = !config.NoPager && isTerminal(stdout) && term.IsTerminal(int(os.Stdin.Fd()))
+	calendarOptions.DimDays = incompleteDays(postMetas)
+	if config.PercentileDays > 0 {
+		calendarOptions.BusyDays = percentileDays(postCounts, config.PercentileDays)
+	}
+
+	stats := computeStats(postCounts, postMetas, time.Now(), config.RestDays)
+	calendarOptions.Anniversary = stats.FirstPost
+	if config.NoTitle {
+		redactTitles(stats.Posts)
+	}
+	for i := range stats.Posts {
+		stats.Posts[i].Path = config.displayPath(stats.Posts[i].Path)
+	}
+
+	if config.CountBySection {
+		from, to, err := displayedRange(postCounts, config)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			return exit(1)
+		}
+		stats.SectionBreakdown = countBySection(postMetas, from, to)
+	}
+
+	renderStart := time.Now()
+	if config.ListSeries {
+		printSeriesList(stdout, postMetas)
+	} else if config.ListTags {
+		printTagList(stdout, postMetas)
+	} else if config.AbandonedTags {
+		printAbandonedTags(stdout, postMetas, config.MinTagUses)
+	} else if config.BestTime {
+		printBestPublishTime(stdout, analyzeBestPublishTime(postMetas))
+	} else if config.LengthHistogram {
+		printPostLengthHistogram(stdout, bucketizePostLengths(stats.Posts))
+	} else if config.ContentHash {
+		// stats.Posts may have anonymized paths, which can't be matched
+		// between runs
+		posts := sortedPosts(postMetas)
+		if config.NoTitle {
+			redactTitles(posts)
+		}
+		if err := reportContentChanges(stdout, hashCachePath(projectID), contentRoot, posts); err != nil {
+			fmt.Fprintf(stdout, "Error comparing content hashes: %v\n", err)
+			return exit(1)
+		}
+	} else if config.Correlation {
+		printTagCorrelations(stdout, correlateTags(postCounts, postMetas))
+	} else if config.GroupByWeek || config.GroupByMonth {
+		from, to, err := displayedRange(postCounts, config)
+		if err != nil {
+			fmt.Fprintf(stdout, "Error: %v\n", err)
+			return exit(1)
+		}
+		p := weekPeriod
+		var target func(PeriodCount) int
+		if config.GroupByMonth {
+			p = monthPeriod
+			if config.Cadence != nil {
+				target = func(count PeriodCount) int { return config.Cadence.monthTarget(count.Start) }
+			}
+		} else if config.Cadence != nil {
+			target = func(PeriodCount) int { return config.Cadence.weekTarget() }
+		}
+		if err := writePeriodCounts(stdout, config.Output, groupByPeriod(postCounts, from, to, p), target); err != nil {
+			fmt.Fprintf(stdout, "Error writing counts: %v\n", err)
+			return exit(1)
+		}
+	} else if config.Output == "json" {
+		if err := writeJSON(stdout, stats); err != nil {
+			fmt.Fprintf(stdout, "Error writing JSON: %v\n", err)
+			return exit(1)
+		}
+	} else if config.CountBySection {
+		printSectionCounts(stdout, stats.SectionBreakdown)
+	} else if config.ShowPaths {
+		printPathTree(stdout, contentRoot, postMetas, config)
+	} else if config.AuthorBreakdown {
+		renderBreakdown(stdout, authorGroups(stats.Posts, config.AuthorLimit), config.Month, calendarOptions)
+	} else if config.TagBreakdown {
+		renderBreakdown(stdout, tagGroups(stats.Posts, config.TagLimit), config.Month, calendarOptions)
+	} else {
+		// With --count-images the count cells show images rather than posts
+		cellCounts := postCounts
+		if config.CountImages && config.ShowCounts {
+			cellCounts = countImagesByDay(postMetas)
+		}
+
+		// Render calendar
+		renderCalendars(stdout, cellCounts, config.Month, calendarOptions)
+
+		if config.TitleList {
+			printTitleList(stdout, postMetas, config)
+		}
+
+		if config.ShowStats {
+			printStats(stdout, stats, config)
+			if config.MovingAverage > 0 {
+				printMovingAverage(stdout, postCounts, config)
+			}
+		}
+	}
+	if config.Elapsed {
+		printElapsed(stderr, walkStats, stats.TotalPosts, time.Since(renderStart), time.Since(start))
+	}
+
+	if config.CountLinks {
+		warnLinkHeavyDays(postCounts, postMetas)
+	}
 
-func parseArgs() (*Config, error) {
-	config := &Config{}
-	args := os.Args[1:]
+	if config.DedupeTitles && warnDuplicateTitles(stdout, postMetas, config) && config.Strict {
+		return exit(3)
+	}
 
-	if len(args) == 0 {
-		return nil, fmt.Errorf("missing project path")
+	if config.CheckFuture && warnFutureDates(stderr, postMetas, config.FutureDays, time.Now(), config) && config.Strict {
+		return exit(1)
 	}
 
-	i := 0
-	for i < len(args) {
-		arg := args[i]
+	if config.CheckPast && warnPastDates(stderr, postMetas, config) && config.Strict {
+		return exit(1)
+	}
 
-		if arg == "-f" || arg == "--filter" {
-			if i+1 >= len(args) {
-				return nil, fmt.Errorf("filter flag requires a value")
-			}
-			config.FilterText = args[i+1]
-			i += 2
-		} else if arg == "-c" || arg == "--counts" {
-			config.ShowCounts = true
-			i++
-		} else if arg == "-m" || arg == "--month" {
-			// Check if next arg exists and is not a flag
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
-				month := args[i+1]
-				config.Month = &month
-				i += 2
-			} else {
-				// No value provided, use current month
-				currentMonth := time.Now().Format("2006-01")
-				config.Month = &currentMonth
-				i++
-			}
-		} else if strings.HasPrefix(arg, "-") {
-			return nil, fmt.Errorf("unknown flag: %s", arg)
-		} else {
-			// This should be the project path
-			if config.ProjectPath == "" {
-				config.ProjectPath = arg
-				i++
-			} else {
-				return nil, fmt.Errorf("unexpected argument: %s", arg)
-			}
+	if config.InfluxFile != "" {
+		if err := exportInflux(config.InfluxFile, projectID, postCounts); err != nil {
+			fmt.Fprintf(stdout, "Error writing InfluxDB export: %v\n", err)
+			return exit(1)
 		}
 	}
 
-	if config.ProjectPath == "" {
-		return nil, fmt.Errorf("missing project path")
+	if config.Metrics {
+		if err := exportMetrics(stderr, config.MetricsFile, stats, walkStats); err != nil {
+			fmt.Fprintf(stdout, "Error writing metrics: %v\n", err)
+			return exit(1)
+		}
 	}
 
-	// Validate month format if provided
-	if config.Month != nil {
-		if _, err := time.Parse("2006-01", *config.Month); err != nil {
-			return nil, fmt.Errorf("invalid month format '%s', expected YYYY-MM", *config.Month)
+	if config.StatsdAddr != "" {
+		if err := exportStatsd(config.StatsdAddr, postCounts); err != nil {
+			fmt.Fprintf(stdout, "Error sending StatsD metrics: %v\n", err)
+			return exit(1)
 		}
 	}
 
-	return config, nil
-}
+	if config.Notify {
+		notifyStreakMilestone(computeStreaks(postCounts, time.Now(), config.RestDays), config.NotifyEvery)
+	}
 
-func getTerminalWidth() int {
-	type winsize struct {
-		Row    uint16
-		Col    uint16
-		Xpixel uint16
-		Ypixel uint16
+	if config.WebhookURL != "" {
+		if err := postWebhook(config.WebhookURL, config.WebhookSecret, stats); err != nil {
+			fmt.Fprintf(stdout, "Warning: Could not send webhook: %v\n", err)
+			if config.Strict {
+				return exit(1)
+			}
+		}
 	}
 
-	ws := &winsize{}
-	retCode, _, errno := syscall.Syscall(syscall.SYS_IOCTL,
-		uintptr(syscall.Stdin),
-		uintptr(syscall.TIOCGWINSZ),
-		uintptr(unsafe.Pointer(ws)))
+	if config.SlackWebhook != "" {
+		month := time.Now()
+		if config.Month != nil {
+			month, _ = time.Parse("2006-01", *config.Month)
+		}
+		if err := postSlackSummary(config.SlackWebhook, month, postCounts, config.Goal, config.RestDays); err != nil {
+			fmt.Fprintf(stdout, "Warning: Could not post Slack summary: %v\n", err)
+			if config.Strict {
+				return exit(1)
+			}
+		}
+	}
 
-	if int(retCode) == -1 || errno != 0 {
-		// Terminal width not available (pipe, non-interactive, etc.)
-		return 80
+	if config.GitHubActions {
+		if err := writeGitHubActions(stdout, config, postCounts); err != nil {
+			fmt.Fprintf(stdout, "Error writing GitHub Actions summary: %v\n", err)
+			return exit(1)
+		}
 	}
 
-	return int(ws.Col)
+	flushOutput()
+	return 0
 }
 
-func main() {
-	config, err := parseArgs()
+// isBundleDir reports whether dir is a leaf bundle, meaning it holds page
+// resources such as images next to its Markdown files.
+func isBundleDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		fmt.Printf("Error: %v\n\n", err)
-		fmt.Println("Usage: hugo-calendar <path-to-hugo-project> [options]")
-		fmt.Println("Options:")
-		fmt.Println("  -f, --filter TEXT    Exclude posts containing TEXT in their body")
-		fmt.Println("  -c, --counts         Show post counts instead of day numbers")
-		fmt.Println("  -m, --month YYYY-MM  Show only the specified month (default: current month)")
-		os.Exit(1)
+		return false
 	}
-
-	postsPath := filepath.Join(config.ProjectPath, "content", "posts")
-
-	// Check if posts directory exists
-	if _, err := os.Stat(postsPath); os.IsNotExist(err) {
-		fmt.Printf("Posts directory not found: %s\n", postsPath)
-		os.Exit(1)
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) != ".md" {
+			return true
+		}
 	}
+	return false
+}
 
-	// Parse all posts and count by date
-	postCounts, err := parsePostsAndCount(postsPath, config.FilterText)
-	if err != nil {
-		fmt.Printf("Error parsing posts: %v\n", err)
-		os.Exit(1)
-	}
+// WalkStats counts what a content walk saw besides the posts it kept.
+type WalkStats struct {
+	Files  int // Files visited
+	Posts  int // Post files parsed
+	Drafts int // Draft posts skipped
 
-	if len(postCounts) == 0 {
-		fmt.Println("No posts found in the Hugo project.")
-		return
+	// Time spent in each phase. Walk is the whole walk, including the
+	// parsing and filtering done along the way.
+	Walk, Parse, Filter time.Duration
+}
+
+// keepFirstPostOfDay drops all but one post from every day, for counting
+// days with posts rather than posts. Dates have no time of day to go by, so
+// the post that comes first by path is kept.
+func keepFirstPostOfDay(postCounts map[string]int, postMetas map[string][]PostMeta) {
+	for dateKey, metas := range postMetas {
+		first := metas[0]
+		for _, meta := range metas[1:] {
+			if meta.Path < first.Path {
+				first = meta
+			}
+		}
+		postMetas[dateKey] = []PostMeta{first}
+		postCounts[dateKey] = 1
 	}
+}
 
-	// Render calendar
-	renderCalendars(postCounts, config.ShowCounts, config.Month)
+// add accumulates other into s, for walks over several projects.
+func (s *WalkStats) add(other WalkStats) {
+	s.Files += other.Files
+	s.Posts += other.Posts
+	s.Drafts += other.Drafts
+	s.Walk += other.Walk
+	s.Parse += other.Parse
+	s.Filter += other.Filter
 }
 
-func parsePostsAndCount(postsPath, filterText string) (map[string]int, error) {
+// parsePostsAndCount reads every post under postsPath, within the project's
+// contentPath, and returns the posts kept per day. Posts that can't be read
+// are skipped with a warning to w.
+func parsePostsAndCount(w io.Writer, contentPath, postsPath string, config *Config) (map[string]int, map[string][]PostMeta, WalkStats, error) {
+	var walkStats WalkStats
 	postCounts := make(map[string]int)
+	postMetas := make(map[string][]PostMeta)
+
+	// Cascaded front matter for each directory, including what it inherits
+	// from the sections above it
+	cascades := make(map[string]map[string]interface{})
+	if config.ApplyCascade {
+		cascades[contentPath] = readCascade(w, contentPath, config)
+	}
 
-	err := filepath.Walk(postsPath, func(path string, info os.FileInfo, err error) error {
+	// With --validate, posts that can't be read are kept for the report,
+	// since they're the worst kind of invalid
+	addInvalid := func(path string, err error) {
+		dateKey := time.Time{}.Format("2006-01-02")
+		postMetas[dateKey] = append(postMetas[dateKey], PostMeta{Path: path, ParseError: err.Error()})
+	}
+
+	walkStart := time.Now()
+	err := filepath.WalkDir(postsPath, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if !entry.IsDir() {
+			walkStats.Files++
+		}
+
+		// Skip ignored sections, which are only matched one level down
+		if entry.IsDir() && filepath.Dir(path) == filepath.Clean(postsPath) {
+			for _, section := range config.IgnoreSections {
+				if filepath.Base(path) == section {
+					return filepath.SkipDir
+				}
+			}
+		}
+
+		// Collect cascades on the way down, before any of the directory's
+		// posts are read
+		if entry.IsDir() && config.ApplyCascade {
+			cascades[path] = mergeCascade(cascades[filepath.Dir(path)], readCascade(w, path, config))
+		}
 
 		// Look for index.md files
-		if info.Name() == "index.md" {
+		if entry.Name() == "index.md" {
+			if config.IgnoreBundles && isBundleDir(filepath.Dir(path)) {
+				logVerbose("Skipping bundle %s", config.displayPath(filepath.Dir(path)))
+				return nil
+			}
+
+			parseStart := time.Now()
 			frontMatter, postBody, err := parsePostFile(path)
+			walkStats.Parse += time.Since(parseStart)
 			if err != nil {
-				fmt.Printf("Warning: Could not parse post file %s: %v\n", path, err)
+				if config.Validate {
+					addInvalid(path, err)
+					return nil
+				}
+				fmt.Fprintf(w, "Warning: Could not parse post file %s: %v\n", config.displayPath(path), err)
 				return nil // Continue processing other files
 			}
+			walkStats.Posts++
+
+			// Everything from here until the post is kept or dropped is
+			// filtering; counting the content of a kept post is parsing
+			filterStart := time.Now()
+			var filterEnd time.Time
+			defer func() {
+				if filterEnd.IsZero() {
+					walkStats.Filter += time.Since(filterStart)
+				} else {
+					walkStats.Filter += filterEnd.Sub(filterStart)
+					walkStats.Parse += time.Since(filterEnd)
+				}
+			}()
+
+			if config.ApplyCascade {
+				frontMatter, err = applyCascade(frontMatter, cascades[filepath.Dir(path)])
+				if err != nil {
+					if config.Validate {
+						addInvalid(path, err)
+						return nil
+					}
+					fmt.Fprintf(w, "Warning: Could not apply cascade to %s: %v\n", config.displayPath(path), err)
+					return nil
+				}
+			}
+
+			// Every post is validated, including the ones the filters
+			// below would drop
+			if config.Validate {
+				dateKey := frontMatter.Date.Format("2006-01-02")
+				postMetas[dateKey] = append(postMetas[dateKey], PostMeta{
+					Path:  path,
+					Title: frontMatter.Title,
+					Date:  frontMatter.Date,
+					Tags:  postTags(frontMatter, config),
+				})
+				return nil
+			}
 
 			// Skip draft posts
 			if frontMatter.Draft {
+				walkStats.Drafts++
 				return nil
 			}
 
 			// Skip posts containing filter text in body
-			if filterText != "" && strings.Contains(postBody, filterText) {
+			if config.FilterText != "" && strings.Contains(postBody, config.FilterText) {
+				return nil
+			}
+			for _, re := range config.FilterRegexes {
+				if re.MatchString(postBody) {
+					return nil
+				}
+			}
+
+			if config.HasAliases && len(frontMatter.Aliases) == 0 {
+				return nil
+			}
+
+			if config.Series != "" && !containsFold(frontMatter.Series, config.Series) {
+				return nil
+			}
+
+			if config.FeaturedOnly && !frontMatter.Featured {
+				return nil
+			}
+
+			// Hugo infers the type from the section when none is set
+			postType := frontMatter.Type
+			if postType == "" {
+				postType = sectionOf(contentPath, path)
+			}
+			if len(config.Types) > 0 && !containsFold(config.Types, postType) {
+				return nil
+			}
+
+			if len(config.Layouts) > 0 && !containsFold(config.Layouts, frontMatter.Layout) {
+				return nil
+			}
+
+			if config.DayOfMonth > 0 && frontMatter.Date.Day() != config.DayOfMonth {
+				return nil
+			}
+
+			if config.ExcludeDays[frontMatter.Date.Format("2006-01-02")] {
+				return nil
+			}
+
+			tags := postTags(frontMatter, config)
+
+			if len(config.FilterTags) > 0 && !containsAnyFold(tags, config.FilterTags) {
+				return nil
+			}
+
+			// Posts missing required front matter are incomplete, which
+			// works like a softer draft
+			incomplete := config.RequireTags && len(tags) == 0
+			if incomplete && config.ExcludeIncomplete {
 				return nil
 			}
 
+			// Skip posts that don't match every field filter
+			for _, filter := range config.FieldFilters {
+				if !fieldMatches(frontMatter.Fields[filter.Name], filter.Value) {
+					return nil
+				}
+			}
+
+			filterEnd = time.Now()
+			meta := PostMeta{
+				Path:        path,
+				Section:     sectionOf(contentPath, path),
+				ContentRoot: contentPath,
+				Title:       frontMatter.Title,
+				Date:        frontMatter.Date,
+				Aliases:     frontMatter.Aliases,
+				Series:      frontMatter.Series,
+				Tags:        tags,
+				Incomplete:  incomplete,
+				Featured:    frontMatter.Featured,
+				Type:        frontMatter.Type,
+				Layout:      frontMatter.Layout,
+				Author:      postAuthor(frontMatter.Fields),
+				WordCount:   len(strings.Fields(postBody)),
+			}
+			if config.CountImages {
+				meta.ImageCount = countImages(postBody)
+			}
+			if config.ContentHash {
+				meta.ContentHash = contentHash(postBody)
+			}
+			if config.CountLinks {
+				meta.LinkCount = countLinks(postBody)
+			}
+			if config.CountCode {
+				meta.CodeBlockCount, meta.TaggedCodeBlockCount = countCodeBlocks(postBody)
+			}
+			if config.CountHeadings {
+				meta.HeadingCounts = countHeadings(postBody)
+				if meta.HeadingCounts[1] == 0 {
+					logVerbose("Warning: %s has no H2 headings", config.displayPath(path))
+				}
+			}
+			if config.Readability {
+				meta.ReadabilityScore = readabilityScore(postBody)
+			}
+			if len(config.ExtractFields) > 0 {
+				meta.ExtraFields = make(map[string]string)
+				for _, name := range config.ExtractFields {
+					if value, ok := frontMatter.Fields[name]; ok {
+						meta.ExtraFields[name] = frontMatterString(value)
+					}
+				}
+			}
+
 			// Count posts by date (day precision)
 			dateKey := frontMatter.Date.Format("2006-01-02")
 			postCounts[dateKey]++
+			postMetas[dateKey] = append(postMetas[dateKey], meta)
 		}
 
 		return nil
 	})
+	walkStats.Walk = time.Since(walkStart)
 
-	return postCounts, err
+	return postCounts, postMetas, walkStats, err
 }
 
 func parsePostFile(filePath string) (*PostFrontMatter, string, error) {
@@ -201,6 +2008,8 @@ func parsePostFile(filePath string) (*PostFrontMatter, string, error) {
 	var frontMatterEnded bool
 
 	for scanner.Scan() {
+		// ScanLines drops the \r of CRLF line endings, so files saved on
+		// Windows match the delimiter too
 		line := scanner.Text()
 
 		if line == "---" {
@@ -230,20 +2039,172 @@ func parsePostFile(filePath string) (*PostFrontMatter, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
+	err = yaml.Unmarshal([]byte(frontMatterYAML), &frontMatter.Fields)
+	if err != nil {
+		return nil, "", err
+	}
 
 	postBody := strings.Join(bodyLines, "\n")
 	return &frontMatter, postBody, nil
 }
 
-func renderCalendars(postCounts map[string]int, showCounts bool, monthFilter *string) {
+// frontMatterString formats a decoded YAML value for display. Lists are
+// joined with commas and dates lose their time component.
+func frontMatterString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format("2006-01-02")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = frontMatterString(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fieldMatches reports whether a decoded front matter value equals want,
+// ignoring case. A list matches if any of its items does.
+func fieldMatches(value interface{}, want string) bool {
+	if items, ok := value.([]interface{}); ok {
+		for _, item := range items {
+			if fieldMatches(item, want) {
+				return true
+			}
+		}
+		return false
+	}
+	return value != nil && strings.EqualFold(frontMatterString(value), want)
+}
+
+// containsFold reports whether values contains want, ignoring case.
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// incompleteDays returns the days on which every post is incomplete.
+func incompleteDays(postMetas map[string][]PostMeta) map[string]bool {
+	days := make(map[string]bool)
+	for dateKey, metas := range postMetas {
+		allIncomplete := len(metas) > 0
+		for _, meta := range metas {
+			allIncomplete = allIncomplete && meta.Incomplete
+		}
+		if allIncomplete {
+			days[dateKey] = true
+		}
+	}
+	return days
+}
+
+// postTags returns the tags of a post, falling back to its keywords with
+// --use-keywords-as-tags.
+func postTags(frontMatter *PostFrontMatter, config *Config) []string {
+	tags := mergeTerms(frontMatter.Tags, frontMatter.Params.Tags)
+	if config.KeywordsAsTags && len(tags) == 0 {
+		tags = frontMatter.Keywords
+	}
+	return tags
+}
+
+// mergeTerms returns the terms in a followed by those in b that a doesn't
+// already contain, ignoring case.
+func mergeTerms(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	merged := append([]string{}, a...)
+	for _, term := range b {
+		if !containsFold(merged, term) {
+			merged = append(merged, term)
+		}
+	}
+	return merged
+}
+
+// containsAnyFold reports whether values contains any of wants, ignoring
+// case.
+func containsAnyFold(values, wants []string) bool {
+	for _, want := range wants {
+		if containsFold(values, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func renderCalendars(w io.Writer, postCounts map[string]int, monthFilter *string, opts CalendarOptions) {
+	months, err := calendarMonths(postCounts, monthFilter, opts)
+	if err != nil {
+		fmt.Fprintf(w, "Error parsing month filter: %v\n", err)
+		return
+	}
+
+	if !opts.Week.IsZero() {
+		fmt.Fprintln(w, weekHeader(opts.Week))
+		fmt.Fprintln(w)
+	}
+
+	// Render calendars in rows
+	renderCalendarGrid(w, months, postCounts, opts, terminalWidth{})
+
+	if opts.Holidays != nil {
+		printHolidayLegend(w, months, opts.Holidays)
+	}
+}
+
+// weekHeader describes the ISO week starting on monday, for example
+// "Week 30, 2024 (July 22–28)".
+func weekHeader(monday time.Time) string {
+	sunday := monday.AddDate(0, 0, 6)
+	year, week := monday.ISOWeek()
+	days := monday.Format("January 2") + "–" + sunday.Format("2")
+	if sunday.Month() != monday.Month() {
+		days = monday.Format("January 2") + "–" + sunday.Format("January 2")
+	}
+	return fmt.Sprintf("Week %d, %d (%s)", week, year, days)
+}
+
+// printHolidayLegend lists the holidays that fall in months, since the
+// calendar cells only have room to mark them.
+func printHolidayLegend(w io.Writer, months []time.Time, holidays *HolidayCalendar) {
+	for _, month := range months {
+		for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+			if name := holidays.Name(day); name != "" {
+				fmt.Fprintf(w, "%s  %s\n", day.Format("2006-01-02"), name)
+			}
+		}
+	}
+}
+
+// calendarMonths returns the first day of every month to display: just the
+// filtered month if one is given, otherwise every month from the earliest to
+// the latest post within the From-To range. With ShowEmptyMonths the range
+// itself sets the first and last month.
+func calendarMonths(postCounts map[string]int, monthFilter *string, opts CalendarOptions) ([]time.Time, error) {
 	var months []time.Time
 
-	if monthFilter != nil {
+	if !opts.Week.IsZero() {
+		// A week can straddle two months, so show both
+		first := time.Date(opts.Week.Year(), opts.Week.Month(), 1, 0, 0, 0, 0, time.UTC)
+		months = append(months, first)
+		if last := opts.Week.AddDate(0, 0, 6); last.Month() != first.Month() {
+			months = append(months, first.AddDate(0, 1, 0))
+		}
+	} else if monthFilter != nil {
 		// Single month mode - parse the target month
 		targetMonth, err := time.Parse("2006-01", *monthFilter)
 		if err != nil {
-			fmt.Printf("Error parsing month filter: %v\n", err)
-			return
+			return nil, err
 		}
 		months = append(months, time.Date(targetMonth.Year(), targetMonth.Month(), 1, 0, 0, 0, 0, time.UTC))
 	} else {
@@ -255,11 +2216,26 @@ func renderCalendars(postCounts map[string]int, showCounts bool, monthFilter *st
 			if err != nil {
 				continue
 			}
+			if !opts.From.IsZero() && date.Before(opts.From) {
+				continue
+			}
+			if !opts.To.IsZero() && !date.Before(opts.To.AddDate(0, 1, 0)) {
+				continue
+			}
 			dates = append(dates, date)
 		}
 
+		if opts.ShowEmptyMonths {
+			if !opts.From.IsZero() {
+				dates = append(dates, opts.From)
+			}
+			if !opts.To.IsZero() {
+				dates = append(dates, opts.To)
+			}
+		}
+
 		if len(dates) == 0 {
-			return
+			return nil, nil
 		}
 
 		// Need to import sort for this
@@ -280,28 +2256,45 @@ func renderCalendars(postCounts map[string]int, showCounts bool, monthFilter *st
 		end := time.Date(maxDate.Year(), maxDate.Month(), 1, 0, 0, 0, 0, time.UTC)
 
 		for !current.After(end) {
-			months = append(months, current)
+			if !opts.OnlyWithPosts || countMonthPosts(postCounts, current) > 0 {
+				months = append(months, current)
+			}
 			current = current.AddDate(0, 1, 0)
 		}
+
+		// Count back over active months only, so gaps don't use up N
+		if opts.Recent > 0 {
+			var active []time.Time
+			for _, month := range months {
+				if countMonthPosts(postCounts, month) > 0 {
+					active = append(active, month)
+				}
+			}
+			if len(active) > opts.Recent {
+				active = active[len(active)-opts.Recent:]
+			}
+			months = active
+		}
 	}
 
-	// Render calendars in rows
-	renderCalendarGrid(months, postCounts, showCounts)
+	return months, nil
 }
 
-func renderCalendarGrid(months []time.Time, postCounts map[string]int, showCounts bool) {
-	// Calculate terminal width and calendars per row
+func renderCalendarGrid(w io.Writer, months []time.Time, postCounts map[string]int, opts CalendarOptions, width terminalWidthProvider) {
+	// Calculate calendars per row
 	const calendarWidth = 22 // Each calendar is 20 chars wide + 2 chars padding
-	terminalWidth := getTerminalWidth()
-	calendarsPerRow := terminalWidth / calendarWidth
+	calendarsPerRow := width.Width() / calendarWidth
+	if opts.Columns > 0 {
+		calendarsPerRow = opts.Columns
+	}
 
 	// Ensure at least one calendar per row
 	if calendarsPerRow < 1 {
 		calendarsPerRow = 1
 	}
 
-	white := color.New(color.FgWhite)
-	brightGreen := color.New(color.FgHiGreen, color.Bold)
+	terminalHeight := getTerminalHeight()
+	linesPrinted := 0
 
 	for i := 0; i < len(months); i += calendarsPerRow {
 		end := i + calendarsPerRow
@@ -311,58 +2304,79 @@ func renderCalendarGrid(months []time.Time, postCounts map[string]int, showCount
 
 		rowMonths := months[i:end]
 
+		// Generate calendar grids for this row
+		calendarGrids := make([][]string, len(rowMonths))
+		maxRows := 0
+
+		for idx, month := range rowMonths {
+			grid := generateCalendarGrid(month, postCounts, opts)
+			calendarGrids[idx] = grid
+			if len(grid) > maxRows {
+				maxRows = len(grid)
+			}
+		}
+
+		// Pause before a row that would scroll earlier rows off screen,
+		// leaving one line for the prompt. Each row has two header lines,
+		// the weeks, and a blank separator line.
+		rowLines := maxRows + 3
+		if opts.Paginate && linesPrinted > 0 && linesPrinted+rowLines > terminalHeight-1 {
+			waitForPager(w)
+			linesPrinted = 0
+		}
+		linesPrinted += rowLines
+
 		// Print month headers
 		for j, month := range rowMonths {
 			if j > 0 {
-				fmt.Print("  ") // 2-space padding between calendars
+				fmt.Fprint(w, "  ") // 2-space padding between calendars
 			}
 			header := month.Format("January 2006")
-			white.Printf("%-20s", header)
+			if opts.ShowEmptyMonths && countMonthPosts(postCounts, month) == 0 {
+				// Abbreviated so the note fits in the calendar width
+				header = month.Format("Jan 2006") + " (no posts)"
+			}
+			opts.Colors.Day.Fprintf(w, "%-20s", header)
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 
 		// Print day headers
 		for j := range rowMonths {
 			if j > 0 {
-				fmt.Print("  ") // 2-space padding between calendars
-			}
-			white.Print("Su Mo Tu We Th Fr Sa")
-		}
-		fmt.Println()
-
-		// Generate calendar grids for this row
-		calendarGrids := make([][]string, len(rowMonths))
-		maxRows := 0
-
-		for idx, month := range rowMonths {
-			grid := generateCalendarGrid(month, postCounts, white, brightGreen, showCounts)
-			calendarGrids[idx] = grid
-			if len(grid) > maxRows {
-				maxRows = len(grid)
+				fmt.Fprint(w, "  ") // 2-space padding between calendars
 			}
+			opts.Colors.Day.Fprint(w, "Su Mo Tu We Th Fr Sa")
 		}
+		fmt.Fprintln(w)
 
 		// Print calendar rows
 		for row := 0; row < maxRows; row++ {
 			for idx, grid := range calendarGrids {
 				if idx > 0 {
-					fmt.Print("  ") // 2-space padding between calendars
+					fmt.Fprint(w, "  ") // 2-space padding between calendars
 				}
 				if row < len(grid) {
-					fmt.Print(grid[row])
+					fmt.Fprint(w, grid[row])
 				} else {
-					fmt.Print(strings.Repeat(" ", 20))
+					fmt.Fprint(w, strings.Repeat(" ", 20))
 				}
 			}
-			fmt.Println()
+			fmt.Fprintln(w)
 		}
 
-		fmt.Println() // Extra space between calendar rows
+		fmt.Fprintln(w) // Extra space between calendar rows
 	}
 }
 
-func generateCalendarGrid(month time.Time, postCounts map[string]int, white, brightGreen *color.Color, showCounts bool) []string {
+func generateCalendarGrid(month time.Time, postCounts map[string]int, opts CalendarOptions) []string {
 	var grid []string
+	colors := opts.Colors
+
+	// Empty months in a range are dimmed entirely
+	white := colors.Day
+	if opts.ShowEmptyMonths && countMonthPosts(postCounts, month) == 0 {
+		white = colors.EmptyMonthDay
+	}
 
 	// First day of month and its weekday
 	firstDay := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
@@ -381,68 +2395,119 @@ func generateCalendarGrid(month time.Time, postCounts map[string]int, white, bri
 	weekRow := 0
 
 	for day <= daysInMonth || weekRow == 0 {
-		var rowParts []string
+		var row strings.Builder
 
-		// For each column (weekday) in this row
+		// For each column (weekday) in this row, with a single space
+		// between columns
 		for col := 0; col < 7; col++ {
+			if col > 0 {
+				row.WriteByte(' ')
+			}
 			if weekRow == 0 && col < startWeekday {
 				// Empty cell before month starts
-				if showCounts {
-					rowParts = append(rowParts, "  ")
-				} else {
-					rowParts = append(rowParts, "  ")
-				}
+				row.WriteString("  ")
 			} else if day <= daysInMonth {
 				// Valid day in month
-				dateKey := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+				date := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+				dateKey := date.Format("2006-01-02")
 				count := postCounts[dateKey]
 				isToday := dateKey == currentDateKey
 
+				// Days with only incomplete posts are dimmed
+				postColor := colors.Post
+				if opts.DimDays[dateKey] {
+					postColor = colors.IncompletePost
+				}
+
+				// Unusually busy days stand out in red
+				if opts.BusyDays[dateKey] {
+					postColor = colors.BusyPost
+				}
+
+				// Holidays are underlined, and red when there is no post
+				// to show in green
+				dayColor := white
+				if opts.RestDays.Skip(date) {
+					dayColor = colors.RestDay
+				}
+				if day == opts.DayOfMonth {
+					dayColor = colors.TargetDay
+				}
+				if opts.Highlight[dateKey] {
+					dayColor = colors.Highlight
+					postColor = colors.HighlightPost
+				}
+				holiday := opts.Holidays.Name(date)
+				if holiday != "" && opts.BusyDays[dateKey] {
+					postColor = colors.BusyHolidayPost
+					dayColor = colors.Holiday
+				} else if holiday != "" {
+					postColor = colors.HolidayPost
+					dayColor = colors.Holiday
+				}
+
+				// With --week only the chosen week's days are colored
+				if !opts.Week.IsZero() && (date.Before(opts.Week) || !date.Before(opts.Week.AddDate(0, 0, 7))) {
+					postColor = colors.EmptyMonthDay
+					dayColor = colors.EmptyMonthDay
+				}
+
 				var dayStr string
-				if showCounts {
+				if opts.ShowCounts {
 					if count > 0 {
 						if isToday {
-							dayStr = color.New(color.FgBlack, color.BgWhite).Sprintf("%2d", count)
+							dayStr = colors.Today.Sprintf("%2d", count)
 						} else {
-							dayStr = brightGreen.Sprintf("%2d", count)
+							dayStr = postColor.Sprintf("%2d", count)
 						}
 					} else {
 						if isToday {
-							dayStr = color.New(color.FgBlack, color.BgWhite).Sprintf(" 0")
+							dayStr = colors.Today.Sprintf(" 0")
 						} else {
-							dayStr = white.Sprintf(" 0")
+							dayStr = dayColor.Sprintf(" 0")
 						}
 					}
 				} else {
 					if count > 0 {
 						if isToday {
-							dayStr = color.New(color.FgBlack, color.BgWhite).Sprintf("%2d", day)
+							dayStr = colors.Today.Sprintf("%2d", day)
 						} else {
-							dayStr = brightGreen.Sprintf("%2d", day)
+							dayStr = postColor.Sprintf("%2d", day)
 						}
 					} else {
 						if isToday {
-							dayStr = color.New(color.FgBlack, color.BgWhite).Sprintf("%2d", day)
+							dayStr = colors.Today.Sprintf("%2d", day)
 						} else {
-							dayStr = white.Sprintf("%2d", day)
+							dayStr = dayColor.Sprintf("%2d", day)
 						}
 					}
 				}
-				rowParts = append(rowParts, dayStr)
+				// The blog's very first post gets a cake in place of the day.
+				// A count is kept and colored instead, so it isn't hidden
+				if dateKey == opts.Anniversary && opts.ShowCounts {
+					if !isToday {
+						dayStr = colors.Anniversary.Sprintf("%2d", count)
+					}
+				} else if dateKey == opts.Anniversary {
+					mark := "🎂"
+					if opts.ASCII {
+						mark = " *"
+					}
+					if isToday {
+						dayStr = colors.Today.Sprint(mark)
+					} else {
+						dayStr = postColor.Sprint(mark)
+					}
+				}
+				row.WriteString(dayStr)
 				day++
 			} else {
 				// Empty cell after month ends
-				if showCounts {
-					rowParts = append(rowParts, "  ")
-				} else {
-					rowParts = append(rowParts, "  ")
-				}
+				row.WriteString("  ")
 			}
 		}
 
-		// Join with single space between columns
-		rowString := strings.Join(rowParts, " ")
-		grid = append(grid, rowString)
+		grid = append(grid, row.String())
 		weekRow++
 
 		// Break if we've processed all days and this row is complete
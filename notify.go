@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// notifyStreakMilestone sends a desktop notification when the current streak
+// is a positive multiple of every days.
+func notifyStreakMilestone(streaks Streaks, every int) {
+	if streaks.Current == 0 || every < 1 || streaks.Current%every != 0 {
+		return
+	}
+	sendNotification(fmt.Sprintf("🔥 %d-day posting streak!", streaks.Current))
+}
+
+// sendNotification shows message using the platform's notification tool. It
+// does nothing if no supported tool is installed.
+func sendNotification(message string) {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("osascript"); err != nil {
+			return
+		}
+		script := fmt.Sprintf("display notification %q with title %q", message, "hugo-calendar")
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		cmd = exec.Command("notify-send", "hugo-calendar", message)
+	}
+
+	// Notifications are best effort, so failures are ignored
+	_ = cmd.Run()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// parsePublicDir builds post counts from a site Hugo has already generated,
+// for when the source content isn't available. Every index.html that
+// carries an article's publication date counts as a post; the home page,
+// section lists, and taxonomy pages don't.
+func parsePublicDir(publicPath string, config *Config) (map[string]int, map[string][]PostMeta, WalkStats, error) {
+	var walkStats WalkStats
+	postCounts := make(map[string]int)
+	postMetas := make(map[string][]PostMeta)
+
+	walkStart := time.Now()
+	err := filepath.WalkDir(publicPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			walkStats.Files++
+		}
+
+		if entry.Name() != "index.html" {
+			return nil
+		}
+
+		parseStart := time.Now()
+		title, date, err := parseHTMLFile(path)
+		walkStats.Parse += time.Since(parseStart)
+		walkStats.Posts++
+		if err != nil {
+			logVerbose("Skipping %s: %v", config.displayPath(path), err)
+			return nil
+		}
+		if date.IsZero() {
+			// List pages, taxonomy pages, and the like have no date
+			return nil
+		}
+
+		dateKey := date.Format("2006-01-02")
+		if config.ExcludeDays[dateKey] {
+			return nil
+		}
+		postCounts[dateKey]++
+		postMetas[dateKey] = append(postMetas[dateKey], PostMeta{Path: path, Title: title, Date: date, Section: sectionOf(publicPath, path), ContentRoot: publicPath})
+		return nil
+	})
+	walkStats.Walk = time.Since(walkStart)
+
+	return postCounts, postMetas, walkStats, err
+}
+
+// parseHTMLFile extracts the title and publication date of a generated
+// page. The date comes from a <meta name="date"> or article:published_time
+// tag, falling back to the first <time datetime> element only on pages whose
+// og:type is article, since list pages show the dates of the posts they
+// list. The date is zero if none is found.
+func parseHTMLFile(filePath string) (string, time.Time, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer file.Close()
+
+	var title string
+	var metaDate, timeDate time.Time
+	article := false
+	inTitle := false
+
+	tokenizer := html.NewTokenizer(file)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// io.EOF at the end of the document
+			date := metaDate
+			if date.IsZero() && article {
+				date = timeDate
+			}
+			return strings.TrimSpace(title), date, nil
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				name := strings.ToLower(htmlAttr(token, "name") + htmlAttr(token, "property"))
+				if metaDate.IsZero() && (name == "date" || name == "article:published_time") {
+					metaDate = parseHTMLDate(htmlAttr(token, "content"))
+				}
+				if name == "og:type" {
+					article = htmlAttr(token, "content") == "article"
+				}
+			case "time":
+				if timeDate.IsZero() {
+					timeDate = parseHTMLDate(htmlAttr(token, "datetime"))
+				}
+			}
+
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			inTitle = false
+		}
+	}
+}
+
+func htmlAttr(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// parseHTMLDate accepts the date formats Hugo themes commonly emit.
+func parseHTMLDate(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if date, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SectionCount is the number of posts in one Hugo section.
+type SectionCount struct {
+	Section string  `json:"section"`
+	Posts   int     `json:"posts"`
+	Percent float64 `json:"percent"` // Share of all counted posts
+}
+
+// sectionOf returns the first path component of path relative to root,
+// which for a content or public directory is the Hugo section. Pages
+// directly under root are in the "/" section.
+func sectionOf(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return ""
+	}
+	section, _, found := strings.Cut(filepath.ToSlash(rel), "/")
+	if !found {
+		return "/"
+	}
+	return section
+}
+
+// countBySection tallies the posts dated within [from, to] by section,
+// busiest section first.
+func countBySection(postMetas map[string][]PostMeta, from, to time.Time) []SectionCount {
+	counts := make(map[string]int)
+	total := 0
+	for _, metas := range postMetas {
+		for _, meta := range metas {
+			if meta.Date.Before(from) || !meta.Date.Before(to) {
+				continue
+			}
+			counts[meta.Section]++
+			total++
+		}
+	}
+
+	breakdown := make([]SectionCount, 0, len(counts))
+	for section, posts := range counts {
+		breakdown = append(breakdown, SectionCount{Section: section, Posts: posts, Percent: percent(posts, total)})
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Posts != breakdown[j].Posts {
+			return breakdown[i].Posts > breakdown[j].Posts
+		}
+		return breakdown[i].Section < breakdown[j].Section
+	})
+	return breakdown
+}
+
+// displayedRange returns the start of the first displayed month and the
+// start of the month after the last one.
+func displayedRange(postCounts map[string]int, config *Config) (time.Time, time.Time, error) {
+	months, err := calendarMonths(postCounts, config.Month, config.calendarOptions())
+	if err != nil || len(months) == 0 {
+		return time.Time{}, time.Time{}, err
+	}
+	return months[0], months[len(months)-1].AddDate(0, 1, 0), nil
+}
+
+// printSectionCounts writes breakdown as a Markdown table.
+func printSectionCounts(w io.Writer, breakdown []SectionCount) {
+	if len(breakdown) == 0 {
+		fmt.Fprintln(w, "No posts in the displayed range.")
+		return
+	}
+
+	width := len("Section")
+	for _, count := range breakdown {
+		width = max(width, len(count.Section))
+	}
+
+	fmt.Fprintf(w, "| %-*s | Posts | %% of Total |\n", width, "Section")
+	fmt.Fprintf(w, "|-%s-|------:|-----------:|\n", strings.Repeat("-", width))
+	for _, count := range breakdown {
+		fmt.Fprintf(w, "| %-*s | %5d | %9.1f%% |\n", width, count.Section, count.Posts, count.Percent)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackMessage struct {
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// postSlackSummary sends a summary of month's posting activity to a Slack
+// incoming webhook. A goal of zero means no goal is set.
+func postSlackSummary(url string, month time.Time, postCounts map[string]int, goal int, rest RestDays) error {
+	monthPosts := countMonthPosts(postCounts, month)
+	streaks := computeStreaks(postCounts, time.Now(), rest)
+
+	fields := []slackText{
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Posts*\n%d", monthPosts)},
+		{Type: "mrkdwn", Text: fmt.Sprintf("*Current streak*\n%d days", streaks.Current)},
+	}
+
+	// Green when on track, yellow when a goal is set and not yet met
+	color := "#2eb886"
+	if goal > 0 {
+		fields = append(fields, slackText{
+			Type: "mrkdwn",
+			Text: fmt.Sprintf("*Goal progress*\n%d/%d (%d%%)", monthPosts, goal, monthPosts*100/goal),
+		})
+		if monthPosts < goal {
+			color = "#daa038"
+		}
+	}
+
+	message := slackMessage{
+		Attachments: []slackAttachment{{
+			Color: color,
+			Blocks: []slackBlock{
+				{Type: "header", Text: &slackText{Type: "plain_text", Text: month.Format("January 2006")}},
+				{Type: "section", Fields: fields},
+			},
+		}},
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(url, body, nil)
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Stats summarizes posting activity. It is the payload sent to webhooks and
+// other integrations, so field names are part of the JSON contract.
+type Stats struct {
+	TotalPosts    int            `json:"total_posts"`
+	ActiveDays    int            `json:"active_days"`
+	FirstPost     string         `json:"first_post,omitempty"`
+	LastPost      string         `json:"last_post,omitempty"`
+	CurrentStreak int            `json:"current_streak"`
+	LongestStreak int            `json:"longest_streak"`
+	Incomplete    int            `json:"incomplete_posts,omitempty"`
+	PostCounts    map[string]int `json:"post_counts"`
+	Months        []MonthStats   `json:"months"`
+	Posts         []PostMeta     `json:"posts"`
+
+	SectionBreakdown []SectionCount `json:"section_breakdown,omitempty"`
+}
+
+// MonthStats summarizes a single month that has at least one post.
+type MonthStats struct {
+	Month string `json:"month"` // YYYY-MM
+	Posts int    `json:"posts"`
+	Links int    `json:"links,omitempty"`
+
+	CodeBlocks       int `json:"code_blocks,omitempty"`
+	TaggedCodeBlocks int `json:"tagged_code_blocks,omitempty"`
+
+	Headings [6]int `json:"headings,omitzero"` // Totals for H1 through H6
+
+	Readability float64 `json:"readability,omitempty"` // Average per post
+
+	Aliased  int `json:"aliased,omitempty"` // Posts with aliases, possibly redirects
+	Featured int `json:"featured,omitempty"`
+
+	Words int `json:"words,omitempty"`
+
+	UniqueTags int `json:"unique_tags"` // Distinct tags used this month
+	NewTags    int `json:"new_tags"`    // Tags used for the first time this month
+}
+
+func computeStats(postCounts map[string]int, postMetas map[string][]PostMeta, today time.Time, rest RestDays) Stats {
+	stats := Stats{PostCounts: postCounts, Posts: sortedPosts(postMetas)}
+
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		count := postCounts[dateKey]
+		if count == 0 {
+			continue
+		}
+		if stats.FirstPost == "" {
+			stats.FirstPost = dateKey
+		}
+		stats.LastPost = dateKey
+		stats.TotalPosts += count
+		stats.ActiveDays++
+	}
+
+	streaks := computeStreaks(postCounts, today, rest)
+	stats.CurrentStreak = streaks.Current
+	stats.LongestStreak = streaks.Longest
+
+	// Posts are sorted by date, so months come out in order
+	var monthTags map[string]bool
+	seenTags := make(map[string]bool)
+	for _, post := range stats.Posts {
+		if post.Incomplete {
+			stats.Incomplete++
+		}
+
+		monthKey := post.Date.Format("2006-01")
+		if len(stats.Months) == 0 || stats.Months[len(stats.Months)-1].Month != monthKey {
+			stats.Months = append(stats.Months, MonthStats{Month: monthKey})
+			monthTags = make(map[string]bool)
+		}
+		month := &stats.Months[len(stats.Months)-1]
+		for _, tag := range post.Tags {
+			if !monthTags[tag] {
+				monthTags[tag] = true
+				month.UniqueTags++
+			}
+			if !seenTags[tag] {
+				seenTags[tag] = true
+				month.NewTags++
+			}
+		}
+		month.Posts++
+		month.Links += post.LinkCount
+		month.CodeBlocks += post.CodeBlockCount
+		month.TaggedCodeBlocks += post.TaggedCodeBlockCount
+		for level, count := range post.HeadingCounts {
+			month.Headings[level] += count
+		}
+		month.Readability += post.ReadabilityScore
+		month.Words += post.WordCount
+		if len(post.Aliases) > 0 {
+			month.Aliased++
+		}
+		if post.Featured {
+			month.Featured++
+		}
+	}
+	for i := range stats.Months {
+		stats.Months[i].Readability /= float64(stats.Months[i].Posts)
+	}
+
+	return stats
+}
+
+type statsColumn struct {
+	header string
+	value  func(MonthStats) string
+	color  func(MonthStats) *color.Color // Optional, for highlighting values
+}
+
+// printStats writes a plain text summary of stats followed by a per-month
+// table. Optional columns only appear when the flag that collects their
+// data is set.
+func printStats(w io.Writer, stats Stats, config *Config) {
+	fmt.Fprintf(w, "Total posts:     %d\n", stats.TotalPosts)
+	fmt.Fprintf(w, "Active days:     %d\n", stats.ActiveDays)
+	fmt.Fprintf(w, "First post:      %s\n", stats.FirstPost)
+	if first, err := time.Parse("2006-01-02", stats.FirstPost); err == nil {
+		fmt.Fprintf(w, "%-16s %s (%s)\n", "Anniversary:", stats.FirstPost, yearsAgo(first, time.Now()))
+	}
+	fmt.Fprintf(w, "Last post:       %s\n", stats.LastPost)
+	fmt.Fprintf(w, "Current streak:  %d days\n", stats.CurrentStreak)
+	fmt.Fprintf(w, "Longest streak:  %d days\n", stats.LongestStreak)
+	if config.RequireTags {
+		fmt.Fprintf(w, "Incomplete:      %d posts\n", stats.Incomplete)
+	}
+	if config.Cadence != nil {
+		fmt.Fprintf(w, "Target cadence:  %s\n", config.Cadence)
+	}
+	if config.WordCountGoal > 0 {
+		fmt.Fprintf(w, "Word goal:       %s words a month\n", thousands(config.WordCountGoal))
+	}
+	if config.DayOfMonth > 0 {
+		hit, total := dayOfMonthHits(stats)
+		fmt.Fprintf(w, "Day %-2d hit:      %d of %d months\n", config.DayOfMonth, hit, total)
+	}
+	fmt.Fprintln(w)
+
+	columns := []statsColumn{
+		{header: "Posts", value: func(m MonthStats) string { return fmt.Sprint(m.Posts) }},
+	}
+	if config.Cadence != nil {
+		// Color the post counts by how they compare to the target
+		target := func(m MonthStats) int {
+			month, _ := time.Parse("2006-01", m.Month)
+			return config.Cadence.monthTarget(month)
+		}
+		columns[0].color = func(m MonthStats) *color.Color { return cadenceColor(m.Posts, target(m)) }
+		columns = append(columns, statsColumn{header: "Target", value: func(m MonthStats) string { return fmt.Sprint(target(m)) }})
+	}
+	if config.CountLinks {
+		columns = append(columns, statsColumn{header: "Links", value: func(m MonthStats) string { return fmt.Sprint(m.Links) }})
+	}
+	if config.CountCode {
+		columns = append(columns,
+			statsColumn{header: "Code", value: func(m MonthStats) string { return fmt.Sprint(m.CodeBlocks) }},
+			statsColumn{header: "Tagged", value: func(m MonthStats) string { return fmt.Sprint(m.TaggedCodeBlocks) }},
+		)
+	}
+	// Aliases and featured flags are read for every post, so show them
+	// whenever there are any
+	var anyAliased, anyFeatured bool
+	for _, month := range stats.Months {
+		anyAliased = anyAliased || month.Aliased > 0
+		anyFeatured = anyFeatured || month.Featured > 0
+	}
+	if anyAliased {
+		columns = append(columns, statsColumn{header: "Aliased", value: func(m MonthStats) string { return fmt.Sprint(m.Aliased) }})
+	}
+	if anyFeatured {
+		columns = append(columns, statsColumn{header: "Featured", value: func(m MonthStats) string { return fmt.Sprint(m.Featured) }})
+	}
+	if config.Readability {
+		columns = append(columns, statsColumn{header: "Reading", value: func(m MonthStats) string { return fmt.Sprintf("%.1f", m.Readability) }})
+	}
+	if config.UniqueTags {
+		columns = append(columns, statsColumn{header: "Unique Tags", value: func(m MonthStats) string { return fmt.Sprint(m.UniqueTags) }})
+	}
+	if config.NewTags {
+		columns = append(columns, statsColumn{header: "New Tags", value: func(m MonthStats) string { return fmt.Sprint(m.NewTags) }})
+	}
+	if config.WordCountGoal > 0 {
+		// Months that reach the goal are green, the rest red
+		columns = append(columns,
+			statsColumn{
+				header: "Words",
+				value:  func(m MonthStats) string { return thousands(m.Words) },
+				color: func(m MonthStats) *color.Color {
+					if m.Words >= config.WordCountGoal {
+						return color.New(color.FgGreen)
+					}
+					return color.New(color.FgRed)
+				},
+			},
+			statsColumn{header: "Goal", value: func(m MonthStats) string {
+				return fmt.Sprintf("%d%%", m.Words*100/config.WordCountGoal)
+			}},
+		)
+	}
+	if config.CountHeadings {
+		// Headings are shown as an average per post so busy months don't
+		// look better structured just for having more posts
+		for level := 0; level < 6; level++ {
+			columns = append(columns, statsColumn{header: fmt.Sprintf("H%d/post", level+1), value: func(m MonthStats) string {
+				return fmt.Sprintf("%.1f", float64(m.Headings[level])/float64(m.Posts))
+			}})
+		}
+	}
+
+	// Columns are at least 8 wide, more for longer headers
+	fmt.Fprintf(w, "%-8s", "Month")
+	for _, column := range columns {
+		fmt.Fprintf(w, "  %8s", column.header)
+	}
+	fmt.Fprintln(w)
+
+	for _, month := range stats.Months {
+		fmt.Fprintf(w, "%-8s", month.Month)
+		for _, column := range columns {
+			// Pad before coloring so escape codes don't upset the widths
+			value := fmt.Sprintf("%*s", max(8, len(column.header)), column.value(month))
+			if column.color != nil {
+				value = column.color(month).Sprint(value)
+			}
+			fmt.Fprintf(w, "  %s", value)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if config.CountPerAuthor {
+		printAuthorCounts(w, stats, config.TopAuthors)
+	}
+}
+
+// printMovingAverage writes each day of the displayed months with its post
+// count and the average over the window days ending on it.
+func printMovingAverage(w io.Writer, postCounts map[string]int, config *Config) {
+	months, err := calendarMonths(postCounts, config.Month, config.calendarOptions())
+	if err != nil || len(months) == 0 {
+		return
+	}
+
+	yellow := color.New(color.FgYellow)
+	fmt.Fprintf(w, "\n%-10s  %5s  %8s\n", "Date", "Posts", fmt.Sprintf("%d-day avg", config.MovingAverage))
+	for _, month := range months {
+		for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+			fmt.Fprintf(w, "%-10s  %5d  %s\n", day.Format("2006-01-02"), postCounts[day.Format("2006-01-02")],
+				yellow.Sprintf("%8.2f", movingAverage(postCounts, day, config.MovingAverage)))
+		}
+	}
+}
+
+// movingAverage returns the mean posts per day over the window days ending
+// on day, counting days without posts as zero.
+func movingAverage(postCounts map[string]int, day time.Time, window int) float64 {
+	total := 0
+	for i := 0; i < window; i++ {
+		total += postCounts[day.AddDate(0, 0, -i).Format("2006-01-02")]
+	}
+	return float64(total) / float64(window)
+}
+
+// percentileDays returns the days whose post count is above the given
+// percentile of all active days, using the nearest-rank method. Days tied
+// with the percentile value aren't included, so a site that posts once a
+// day every day has no outliers.
+func percentileDays(postCounts map[string]int, percentile int) map[string]bool {
+	var counts []int
+	for _, count := range postCounts {
+		if count > 0 {
+			counts = append(counts, count)
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	sort.Ints(counts)
+
+	rank := (percentile*len(counts) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	threshold := counts[rank-1]
+
+	days := make(map[string]bool)
+	for dateKey, count := range postCounts {
+		if count > threshold {
+			days[dateKey] = true
+		}
+	}
+	return days
+}
+
+// countMonthPosts returns the total number of posts in the month containing
+// month.
+func countMonthPosts(postCounts map[string]int, month time.Time) int {
+	prefix := month.Format("2006-01")
+	total := 0
+	for dateKey, count := range postCounts {
+		if strings.HasPrefix(dateKey, prefix) {
+			total += count
+		}
+	}
+	return total
+}
+
+// sortedPosts flattens postMetas into a single list ordered by date, then
+// path.
+func sortedPosts(postMetas map[string][]PostMeta) []PostMeta {
+	posts := []PostMeta{}
+	for _, metas := range postMetas {
+		posts = append(posts, metas...)
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].Date.Equal(posts[j].Date) {
+			return posts[i].Date.Before(posts[j].Date)
+		}
+		return posts[i].Path < posts[j].Path
+	})
+	return posts
+}
+
+// redactTitles replaces every title in posts for --no-title.
+func redactTitles(posts []PostMeta) {
+	for i := range posts {
+		posts[i].Title = "[redacted]"
+	}
+}
+
+// countImagesByDay totals the image counts of every post on each day.
+func countImagesByDay(postMetas map[string][]PostMeta) map[string]int {
+	imageCounts := make(map[string]int)
+	for dateKey, metas := range postMetas {
+		for _, meta := range metas {
+			imageCounts[dateKey] += meta.ImageCount
+		}
+	}
+	return imageCounts
+}
+
+// writeJSON writes stats to w as indented JSON.
+func writeJSON(w io.Writer, stats Stats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
+
+// yearsAgo describes how many whole years before now date was, such as
+// "6 years ago".
+func yearsAgo(date, now time.Time) string {
+	years := now.Year() - date.Year()
+	if now.Month() < date.Month() || (now.Month() == date.Month() && now.Day() < date.Day()) {
+		years--
+	}
+	switch {
+	case years < 1:
+		return "less than a year ago"
+	case years == 1:
+		return "1 year ago"
+	default:
+		return fmt.Sprintf("%d years ago", years)
+	}
+}
+
+// dayOfMonthHits counts the months from the first post to the last that
+// have a post, for --day-of-month where only posts on that day are kept.
+func dayOfMonthHits(stats Stats) (hit, total int) {
+	if len(stats.Months) == 0 {
+		return 0, 0
+	}
+	first, _ := time.Parse("2006-01", stats.Months[0].Month)
+	last, _ := time.Parse("2006-01", stats.Months[len(stats.Months)-1].Month)
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		total++
+	}
+	return len(stats.Months), total
+}
+
+// thousands formats n with commas between groups of three digits.
+func thousands(n int) string {
+	digits := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + thousands(-n)
+	}
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Streaks struct {
+	Current int // Consecutive posting days ending today (or yesterday)
+	Longest int // Longest run of consecutive posting days ever
+}
+
+// RestDays describes days off that neither extend nor break a streak when
+// nothing is posted on them.
+type RestDays struct {
+	Holidays *HolidayCalendar      // Public holidays to skip, nil for none
+	Weekdays map[time.Weekday]bool // Days of the week taken off every week
+}
+
+// Skip reports whether date is a rest day.
+func (r RestDays) Skip(date time.Time) bool {
+	return r.Weekdays[date.Weekday()] || r.Holidays.Name(date) != ""
+}
+
+// parseWeekdays parses a comma-separated list of day names, either in full
+// or abbreviated to three letters, as in "sat,sun".
+func parseWeekdays(list string) (map[time.Weekday]bool, error) {
+	weekdays := make(map[time.Weekday]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			full := strings.ToLower(day.String())
+			if name == full || name == full[:3] {
+				weekdays[day] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown day name '%s'", name)
+		}
+	}
+	return weekdays, nil
+}
+
+// parseRestWeekdays parses a list of rest days like parseWeekdays, but
+// rejects every day of the week, since streaks could then never end and the
+// search for the next posting day would never finish.
+func parseRestWeekdays(list string) (map[time.Weekday]bool, error) {
+	weekdays, err := parseWeekdays(list)
+	if err != nil {
+		return nil, err
+	}
+	if len(weekdays) == 7 {
+		return nil, fmt.Errorf("rest days can't include every day of the week")
+	}
+	return weekdays, nil
+}
+
+// computeStreaks finds the current and longest runs of consecutive days with
+// at least one post. The current streak is still alive if the last post was
+// yesterday, since today may not be over yet. Rest days without posts are
+// stepped over; posts on rest days still count.
+func computeStreaks(postCounts map[string]int, today time.Time, rest RestDays) Streaks {
+	var streaks Streaks
+
+	// Longest streak: walk the active days in order and extend runs of
+	// dates separated only by rest days
+	run := 0
+	var previous time.Time
+	for _, dateKey := range sortedDateKeys(postCounts) {
+		if postCounts[dateKey] == 0 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", dateKey)
+		if err != nil {
+			continue
+		}
+		if run > 0 && nextStreakDay(previous, rest).Equal(date) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > streaks.Longest {
+			streaks.Longest = run
+		}
+		previous = date
+	}
+
+	// Current streak: count backwards from today
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if postCounts[day.Format("2006-01-02")] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for {
+		for postCounts[day.Format("2006-01-02")] == 0 && rest.Skip(day) {
+			day = day.AddDate(0, 0, -1)
+		}
+		if postCounts[day.Format("2006-01-02")] == 0 {
+			break
+		}
+		streaks.Current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return streaks
+}
+
+// nextStreakDay returns the first day after date that isn't a rest day.
+func nextStreakDay(date time.Time, rest RestDays) time.Time {
+	next := date.AddDate(0, 0, 1)
+	for rest.Skip(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// pathNode is a directory or post file in the --show-paths tree.
+type pathNode struct {
+	children map[string]*pathNode
+	post     *PostMeta // Set for post files
+}
+
+// printPathTree writes the posts in postMetas as a tree of the directories
+// under root, labelling each post file with its date, tag count, and word
+// count. With --ascii the tree is drawn without box-drawing characters.
+func printPathTree(w io.Writer, root string, postMetas map[string][]PostMeta, config *Config) {
+	tree := &pathNode{children: make(map[string]*pathNode)}
+	for _, post := range sortedPosts(postMetas) {
+		rel, err := filepath.Rel(root, post.Path)
+		if err != nil {
+			continue
+		}
+		node := tree
+		for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+			if config.Anonymize {
+				name = shortHash(name, 8)
+			}
+			child, ok := node.children[name]
+			if !ok {
+				child = &pathNode{children: make(map[string]*pathNode)}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.post = &post
+	}
+
+	label := config.displayPath(root)
+	if rel, err := filepath.Rel(config.ProjectPath, root); err == nil && !config.Anonymize && !strings.HasPrefix(rel, "..") {
+		label = rel
+	}
+	fmt.Fprintf(w, "%s/\n", filepath.ToSlash(label))
+	printPathNode(w, tree, "", config.ASCII)
+}
+
+func printPathNode(w io.Writer, node *pathNode, prefix string, ascii bool) {
+	branch, last, pipe := "├── ", "└── ", "│   "
+	if ascii {
+		branch, last, pipe = "+-- ", "`-- ", "|   "
+	}
+
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := node.children[name]
+		connector, childPrefix := branch, prefix+pipe
+		if i == len(names)-1 {
+			connector, childPrefix = last, prefix+"    "
+		}
+
+		if child.post != nil {
+			fmt.Fprintf(w, "%s%s%s (%s, %d tags, %d words)\n", prefix, connector, name,
+				child.post.Date.Format("2006-01-02"), len(child.post.Tags), child.post.WordCount)
+			continue
+		}
+		fmt.Fprintf(w, "%s%s%s/\n", prefix, connector, name)
+		printPathNode(w, child, childPrefix, ascii)
+	}
+}
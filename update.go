@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// version is the release this binary was built from, set at build time
+// with -ldflags "-X main.version=1.2.3".
+var version = "dev"
+
+// releaseAPIURL is the GitHub API endpoint for the latest release.
+var releaseAPIURL = "https://api.github.com/repos/aaronbieber/hugo-calendar/releases/latest"
+
+type releaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+type release struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+// latestRelease fetches the newest published release.
+func latestRelease(timeout time.Duration) (*release, error) {
+	req, err := http.NewRequest(http.MethodGet, releaseAPIURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("release lookup returned %s", resp.Status)
+	}
+
+	var latest release
+	if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+		return nil, err
+	}
+	return &latest, nil
+}
+
+// newerVersion reports whether latest is a higher version than current.
+// Versions are compared numerically part by part, ignoring a leading "v".
+// A development build is older than every release.
+func newerVersion(latest, current string) bool {
+	if current == "dev" {
+		return true
+	}
+	latestParts := strings.Split(strings.TrimPrefix(latest, "v"), ".")
+	currentParts := strings.Split(strings.TrimPrefix(current, "v"), ".")
+	for i := 0; i < max(len(latestParts), len(currentParts)); i++ {
+		var l, c int
+		if i < len(latestParts) {
+			l, _ = strconv.Atoi(latestParts[i])
+		}
+		if i < len(currentParts) {
+			c, _ = strconv.Atoi(currentParts[i])
+		}
+		if l != c {
+			return l > c
+		}
+	}
+	return false
+}
+
+// checkUpdate reports to w whether a newer release is available. Failing to
+// reach GitHub is only a warning, so scheduled checks don't fail on it.
+func checkUpdate(w io.Writer) {
+	latest, err := latestRelease(5 * time.Second)
+	if err != nil {
+		fmt.Fprintf(w, "Warning: Could not check for updates: %v\n", err)
+		return
+	}
+	if newerVersion(latest.TagName, version) {
+		fmt.Fprintf(w, "New version available: %s (current: %s)\n",
+			strings.TrimPrefix(latest.TagName, "v"), strings.TrimPrefix(version, "v"))
+	} else {
+		fmt.Fprintln(w, "Already up to date")
+	}
+}
+
+// selfUpdate replaces the running binary with the latest release for this
+// platform, after checking it against the release's checksums file. The
+// outcome is reported to w.
+func selfUpdate(w io.Writer) error {
+	latest, err := latestRelease(10 * time.Second)
+	if err != nil {
+		return err
+	}
+	if !newerVersion(latest.TagName, version) {
+		fmt.Fprintf(w, "Already up to date (%s)\n", version)
+		return nil
+	}
+
+	binaryName := fmt.Sprintf("hugo-calendar_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	var binaryURL, checksumsURL string
+	for _, asset := range latest.Assets {
+		switch asset.Name {
+		case binaryName:
+			binaryURL = asset.URL
+		case "checksums.txt":
+			checksumsURL = asset.URL
+		}
+	}
+	if binaryURL == "" {
+		return fmt.Errorf("release %s has no binary for %s/%s", latest.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	if checksumsURL == "" {
+		return fmt.Errorf("release %s has no checksums.txt", latest.TagName)
+	}
+
+	checksums, err := fetchURL(checksumsURL)
+	if err != nil {
+		return err
+	}
+	expected := ""
+	scanner := bufio.NewScanner(strings.NewReader(string(checksums)))
+	for scanner.Scan() {
+		// Lines are "<sha256>  <file name>", as written by sha256sum
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == binaryName {
+			expected = fields[0]
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("checksums.txt has no entry for %s", binaryName)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+
+	// Download next to the binary so the final rename stays on one file
+	// system and is atomic
+	tmp, err := os.CreateTemp(filepath.Dir(executable), ".hugo-calendar-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(binaryURL)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		tmp.Close()
+		return fmt.Errorf("download returned %s", resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", binaryName, expected, actual)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), executable); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Updated to %s\n", latest.TagName)
+	return nil
+}
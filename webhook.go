@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// postWebhook sends stats as JSON to url. When secret is set the body is
+// signed the same way GitHub signs its webhooks, so receivers can reuse
+// their existing verification code.
+func postWebhook(url, secret string, stats Stats) error {
+	body, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	return postJSON(url, body, header)
+}
+
+// postJSON POSTs a JSON body to url and treats any non-2xx response as an
+// error.
+func postJSON(url string, body []byte, header http.Header) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	return nil
+}